@@ -26,7 +26,7 @@ locals {
       city_name = city.name
       name      = store.name
       address   = store.address
-      type      = title(store.type)
+      type      = store.type
     }
   }]...)
 }
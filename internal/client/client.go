@@ -79,6 +79,7 @@ func (c *SendoraCityClient) DoList(url string, filters map[string]string) (*http
 	for key, value := range filters {
 		querry.Add(key, value)
 	}
+	req.URL.RawQuery = querry.Encode()
 	return c.doRequest(req)
 }
 
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// addressAbbreviations maps French street type abbreviations to their full form.
+var addressAbbreviations = map[string]string{
+	"all":  "allee",
+	"av":   "avenue",
+	"ave":  "avenue",
+	"bd":   "boulevard",
+	"bld":  "boulevard",
+	"blvd": "boulevard",
+	"bvd":  "boulevard",
+	"ch":   "chemin",
+	"che":  "chemin",
+	"chem": "chemin",
+	"crs":  "cours",
+	"esp":  "esplanade",
+	"fbg":  "faubourg",
+	"imp":  "impasse",
+	"pass": "passage",
+	"pl":   "place",
+	"prom": "promenade",
+	"qu":   "quai",
+	"r":    "rue",
+	"res":  "residence",
+	"rte":  "route",
+	"sq":   "square",
+	"vla":  "villa",
+}
+
+// addressAccents maps accented letters found in French addresses to their
+// unaccented form.
+var addressAccents = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "ç", "c", "é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "ô", "o", "ö", "o", "ù", "u", "û", "u", "ü", "u", "ÿ", "y",
+	"œ", "oe", "æ", "ae",
+	"À", "A", "Â", "A", "Ä", "A", "Ç", "C", "É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Î", "I", "Ï", "I", "Ô", "O", "Ö", "O", "Ù", "U", "Û", "U", "Ü", "U", "Ÿ", "Y",
+	"Œ", "OE", "Æ", "AE",
+)
+
+// addressNormalization selects the transformations applied to addresses before
+// comparing them, configured by the provider address_normalization attribute.
+type addressNormalization struct {
+	// CollapseWhitespace trims addresses and turns runs of whitespace, commas
+	// and hyphens into a single space.
+	CollapseWhitespace bool
+	// FoldCase lowercases addresses.
+	FoldCase bool
+	// FoldAccents removes the accents of letters.
+	FoldAccents bool
+	// ExpandAbbreviations replaces abbreviated street types with their full
+	// form, as listed in addressAbbreviations.
+	ExpandAbbreviations bool
+}
+
+// defaultAddressNormalization applies every transformation.
+var defaultAddressNormalization = addressNormalization{
+	CollapseWhitespace:  true,
+	FoldCase:            true,
+	FoldAccents:         true,
+	ExpandAbbreviations: true,
+}
+
+// normalize returns the canonical form of an address.
+func (n addressNormalization) normalize(address string) string {
+	if n.FoldAccents {
+		address = addressAccents.Replace(address)
+	}
+	if n.FoldCase {
+		address = strings.ToLower(address)
+	}
+	var words []string
+	if n.CollapseWhitespace {
+		words = strings.FieldsFunc(address, func(r rune) bool {
+			return unicode.IsSpace(r) || r == ',' || r == '-'
+		})
+	} else {
+		words = strings.Split(address, " ")
+	}
+	if n.ExpandAbbreviations {
+		for i, word := range words {
+			if full, ok := addressAbbreviations[strings.ToLower(strings.TrimSuffix(word, "."))]; ok {
+				words[i] = full
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// equal reports whether two addresses have the same canonical form.
+func (n addressNormalization) equal(a, b string) bool {
+	return n.normalize(a) == n.normalize(b)
+}
+
+// normalizedAddressValue returns the canonical form of an address value, null
+// or unknown along with it.
+func (n addressNormalization) normalizedAddressValue(address types.String) types.String {
+	if address.IsNull() || address.IsUnknown() {
+		return address
+	}
+	return types.StringValue(n.normalize(address.ValueString()))
+}
+
+// modifyPlanAddress plans the normalized_address and the address components of
+// a house or store from its planned address.
+func modifyPlanAddress(ctx context.Context, n addressNormalization, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var address AddressValue
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("address"), &address)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	streetNumber, streetType, streetName, postalCode := addressComponentValues(address.StringValue)
+	for attribute, value := range map[string]types.String{
+		"normalized_address": n.normalizedAddressValue(address.StringValue),
+		"street_number":      streetNumber,
+		"street_type":        streetType,
+		"street_name":        streetName,
+		"postal_code":        postalCode,
+	} {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attribute), value)...)
+	}
+}
@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+var _ datasource.DataSource = &AddressLookupDataSource{}
+
+func NewAddressLookupDataSource() datasource.DataSource {
+	return &AddressLookupDataSource{}
+}
+
+type AddressLookupDataSource struct {
+	client               *client.SendoraCityClient
+	addressNormalization addressNormalization
+	nameAffixes          nameAffixes
+}
+
+type AddressLookupDataSourceModel struct {
+	Address           types.String                    `tfsdk:"address"`
+	CityId            types.Int64                     `tfsdk:"city_id"`
+	NormalizedAddress types.String                    `tfsdk:"normalized_address"`
+	Results           []AddressLookupDataSourceResult `tfsdk:"results"`
+}
+
+type AddressLookupDataSourceResult struct {
+	EntityType  types.String `tfsdk:"entity_type"`
+	Id          types.Int64  `tfsdk:"id"`
+	CityId      types.Int64  `tfsdk:"city_id"`
+	Address     types.String `tfsdk:"address"`
+	Inhabitants types.Int64  `tfsdk:"inhabitants"`
+	Name        types.String `tfsdk:"name"`
+	Type        types.String `tfsdk:"type"`
+}
+
+func (d *AddressLookupDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_address_lookup"
+}
+
+func (d *AddressLookupDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Houses and stores located at a given address",
+
+		Attributes: map[string]schema.Attribute{
+			"address": schema.StringAttribute{
+				MarkdownDescription: "Address to look for, compared following the provider `address_normalization`",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, maxTextLength),
+				},
+			},
+			"city_id": schema.Int64Attribute{
+				MarkdownDescription: "Only look for houses and stores of this city",
+				Optional:            true,
+				Validators:          idValidators(),
+			},
+			"normalized_address": schema.StringAttribute{
+				MarkdownDescription: "Normalized form of the address used for the comparison",
+				Computed:            true,
+			},
+			"results": schema.ListNestedAttribute{
+				MarkdownDescription: "Houses and stores located at the address",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"entity_type": schema.StringAttribute{
+							MarkdownDescription: "Entity type, either `house` or `store`",
+							Computed:            true,
+						},
+						"id": schema.Int64Attribute{
+							MarkdownDescription: "House or store identifier",
+							Computed:            true,
+						},
+						"city_id": schema.Int64Attribute{
+							MarkdownDescription: "House or store city identifier",
+							Computed:            true,
+						},
+						"address": schema.StringAttribute{
+							MarkdownDescription: "House or store address, as stored by the API",
+							Computed:            true,
+						},
+						"inhabitants": schema.Int64Attribute{
+							MarkdownDescription: "House inhabitants count, null for stores",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Store name, null for houses",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Store type, null for houses",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AddressLookupDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
+				"Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+	d.addressNormalization = providerData.AddressNormalization
+	d.nameAffixes = providerData.NameAffixes
+}
+
+func (d *AddressLookupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AddressLookupDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filters := map[string]string{}
+	if !data.CityId.IsNull() {
+		filters["cityid"] = strconv.FormatInt(data.CityId.ValueInt64(), 10)
+	}
+
+	var houses []House
+	var stores []Store
+	errs := runConcurrently(2, maxConcurrentRequests, func(i int) (err error) {
+		if i == 0 {
+			houses, err = listHouses(d.client, filters)
+		} else {
+			stores, err = listStores(d.client, filters)
+		}
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to look up address, got error: %s", err))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	normalizedAddress := d.addressNormalization.normalize(data.Address.ValueString())
+	inCity := func(cityId int) bool {
+		return data.CityId.IsNull() || int64(cityId) == data.CityId.ValueInt64()
+	}
+
+	sort.SliceStable(houses, func(i, j int) bool {
+		return createdBefore(houses[i].Timestamp, houses[i].Id, houses[j].Timestamp, houses[j].Id)
+	})
+	sort.SliceStable(stores, func(i, j int) bool {
+		return createdBefore(stores[i].Timestamp, stores[i].Id, stores[j].Timestamp, stores[j].Id)
+	})
+
+	data.Results = []AddressLookupDataSourceResult{}
+	for _, house := range houses {
+		if !inCity(house.CityId) || d.addressNormalization.normalize(house.Address) != normalizedAddress {
+			continue
+		}
+		data.Results = append(data.Results, AddressLookupDataSourceResult{
+			EntityType:  types.StringValue("house"),
+			Id:          types.Int64Value(int64(house.Id)),
+			CityId:      types.Int64Value(int64(house.CityId)),
+			Address:     types.StringValue(house.Address),
+			Inhabitants: int64Value(house.Inhabitants),
+			Name:        types.StringNull(),
+			Type:        types.StringNull(),
+		})
+	}
+	for _, store := range stores {
+		if !inCity(store.CityId) || d.addressNormalization.normalize(store.Address) != normalizedAddress {
+			continue
+		}
+		data.Results = append(data.Results, AddressLookupDataSourceResult{
+			EntityType:  types.StringValue("store"),
+			Id:          types.Int64Value(int64(store.Id)),
+			CityId:      types.Int64Value(int64(store.CityId)),
+			Address:     types.StringValue(store.Address),
+			Inhabitants: types.Int64Null(),
+			Name:        d.nameAffixes.configNameValue(types.StringValue(store.Name)),
+			Type:        types.StringValue(store.Type),
+		})
+	}
+	data.NormalizedAddress = types.StringValue(normalizedAddress)
+
+	tflog.Trace(ctx, "read address lookup from a data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAddressLookupDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccAddressLookupDataSourceConfig("data-address-lookup-test", "  6 AV. Anatole   France"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sendoracity_address_lookup.test", "normalized_address", "6 avenue anatole france"),
+					resource.TestCheckResourceAttr("data.sendoracity_address_lookup.test", "results.#", "2"),
+					resource.TestCheckResourceAttr("data.sendoracity_address_lookup.test", "results.0.entity_type", "house"),
+					resource.TestCheckResourceAttrPair("data.sendoracity_address_lookup.test", "results.0.id", "sendoracity_house.test", "id"),
+					resource.TestCheckResourceAttr("data.sendoracity_address_lookup.test", "results.1.entity_type", "store"),
+					resource.TestCheckResourceAttrPair("data.sendoracity_address_lookup.test", "results.1.id", "sendoracity_store.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAddressLookupDataSourceConfig(cityName, address string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "sendoracity_house" "test" {
+  city_id     = sendoracity_city.test.id
+  address     = "6 avenue Anatole France"
+  inhabitants = 2
+}
+
+resource "sendoracity_store" "test" {
+  city_id = sendoracity_city.test.id
+  address = "6 Avenue Anatole France"
+  name    = "Store 1"
+  type    = "Food"
+}
+
+data "sendoracity_address_lookup" "test" {
+	address = "%s"
+	city_id = sendoracity_city.test.id
+
+	depends_on = [sendoracity_house.test, sendoracity_store.test]
+}
+`, testAccCityResourceConfig(cityName), address)
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// addressStreetTypes lists the full form of the French street types recognized
+// by parseAddress. Abbreviations are expanded with addressAbbreviations first.
+var addressStreetTypes = map[string]bool{
+	"allee":       true,
+	"avenue":      true,
+	"boulevard":   true,
+	"chaussee":    true,
+	"chemin":      true,
+	"cite":        true,
+	"cours":       true,
+	"esplanade":   true,
+	"faubourg":    true,
+	"hameau":      true,
+	"impasse":     true,
+	"lotissement": true,
+	"parvis":      true,
+	"passage":     true,
+	"place":       true,
+	"promenade":   true,
+	"quai":        true,
+	"residence":   true,
+	"rond-point":  true,
+	"route":       true,
+	"rue":         true,
+	"sentier":     true,
+	"square":      true,
+	"villa":       true,
+	"voie":        true,
+}
+
+var (
+	// addressNumberPattern matches a street number at the start of an address,
+	// with an optional repetition index such as bis, ter or a letter stuck to
+	// the number.
+	addressNumberPattern = regexp.MustCompile(`(?i)^(\d+(?:\s*(?:bis|ter|quater)\b|[a-z]\b)?)[\s,]*`)
+	// addressPostalCodePattern matches a French postal code, followed by the
+	// city name.
+	addressPostalCodePattern = regexp.MustCompile(`[\s,]+(\d{5})(?:\s.*)?$`)
+)
+
+// parsedAddress holds the components of an address. Components missing from
+// the address are empty.
+type parsedAddress struct {
+	StreetNumber string
+	StreetType   string
+	StreetName   string
+	PostalCode   string
+}
+
+// parseAddress splits a French address such as "5 av. Anatole France, 75001
+// Paris" into its components. Street types are returned in their full form,
+// lowercase and without accents, as in "avenue". Addresses without a known
+// street type have their whole street part as street name.
+func parseAddress(address string) parsedAddress {
+	parsed := parsedAddress{}
+	rest := strings.Join(strings.Fields(address), " ")
+
+	if match := addressPostalCodePattern.FindStringSubmatchIndex(rest); match != nil {
+		parsed.PostalCode = rest[match[2]:match[3]]
+		rest = rest[:match[0]]
+	}
+
+	if match := addressNumberPattern.FindStringSubmatch(rest); match != nil {
+		parsed.StreetNumber = strings.Join(strings.Fields(match[1]), " ")
+		rest = rest[len(match[0]):]
+	}
+
+	words := strings.Fields(strings.Trim(rest, " ,"))
+	if len(words) > 1 {
+		streetType := strings.ToLower(addressAccents.Replace(strings.TrimSuffix(words[0], ".")))
+		if full, ok := addressAbbreviations[streetType]; ok {
+			streetType = full
+		}
+		if addressStreetTypes[streetType] {
+			parsed.StreetType = streetType
+			words = words[1:]
+		}
+	}
+	parsed.StreetName = strings.Join(words, " ")
+	return parsed
+}
+
+// addressComponentValues returns the components of an address as Terraform
+// values, null when missing from the address and unknown along with it.
+func addressComponentValues(address types.String) (streetNumber, streetType, streetName, postalCode types.String) {
+	if address.IsNull() || address.IsUnknown() {
+		return address, address, address, address
+	}
+
+	parsed := parseAddress(address.ValueString())
+	return stringValue(parsed.StreetNumber), stringValue(parsed.StreetType), stringValue(parsed.StreetName), stringValue(parsed.PostalCode)
+}
@@ -0,0 +1,71 @@
+package provider
+
+import "testing"
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    parsedAddress
+	}{
+		{
+			address: "5 avenue Anatole France",
+			want:    parsedAddress{StreetNumber: "5", StreetType: "avenue", StreetName: "Anatole France"},
+		},
+		{
+			address: "10 place Anatole France",
+			want:    parsedAddress{StreetNumber: "10", StreetType: "place", StreetName: "Anatole France"},
+		},
+		{
+			address: "7 avenue des champs elysees",
+			want:    parsedAddress{StreetNumber: "7", StreetType: "avenue", StreetName: "des champs elysees"},
+		},
+		{
+			address: "21 rue Jean Jaures",
+			want:    parsedAddress{StreetNumber: "21", StreetType: "rue", StreetName: "Jean Jaures"},
+		},
+		{
+			address: "  6 AV. Anatole   France",
+			want:    parsedAddress{StreetNumber: "6", StreetType: "avenue", StreetName: "Anatole France"},
+		},
+		{
+			address: "7 bis av. des Champs-Élysées, 75008 Paris",
+			want:    parsedAddress{StreetNumber: "7 bis", StreetType: "avenue", StreetName: "des Champs-Élysées", PostalCode: "75008"},
+		},
+		{
+			address: "12B, Allée des Tilleuls 10000 Troyes",
+			want:    parsedAddress{StreetNumber: "12B", StreetType: "allee", StreetName: "des Tilleuls", PostalCode: "10000"},
+		},
+		{
+			address: "1 r de la Paix",
+			want:    parsedAddress{StreetNumber: "1", StreetType: "rue", StreetName: "de la Paix"},
+		},
+		{
+			address: "3 rue du 14 Juillet",
+			want:    parsedAddress{StreetNumber: "3", StreetType: "rue", StreetName: "du 14 Juillet"},
+		},
+		{
+			address: "Place Stanislas",
+			want:    parsedAddress{StreetType: "place", StreetName: "Stanislas"},
+		},
+		{
+			address: "Lieu-dit Les Granges",
+			want:    parsedAddress{StreetName: "Lieu-dit Les Granges"},
+		},
+		{
+			address: "Rue",
+			want:    parsedAddress{StreetName: "Rue"},
+		},
+		{
+			address: "",
+			want:    parsedAddress{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.address, func(t *testing.T) {
+			if got := parseAddress(test.address); got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAddressNormalize(t *testing.T) {
+	tests := []struct {
+		name          string
+		normalization addressNormalization
+		address       string
+		want          string
+	}{
+		{
+			name:          "all transformations",
+			normalization: defaultAddressNormalization,
+			address:       "  7 Av. des Champs-Élysées ",
+			want:          "7 avenue des champs elysees",
+		},
+		{
+			name:          "commas",
+			normalization: defaultAddressNormalization,
+			address:       "5, bd Anatole France",
+			want:          "5 boulevard anatole france",
+		},
+		{
+			name:          "no transformation",
+			normalization: addressNormalization{},
+			address:       " 7 Av. des  Champs-Élysées",
+			want:          " 7 Av. des  Champs-Élysées",
+		},
+		{
+			name:          "whitespace only",
+			normalization: addressNormalization{CollapseWhitespace: true},
+			address:       " 7 Av. des  Champs-Élysées",
+			want:          "7 Av. des Champs Élysées",
+		},
+		{
+			name:          "case only",
+			normalization: addressNormalization{FoldCase: true},
+			address:       "7 Avenue des Champs-Élysées",
+			want:          "7 avenue des champs-élysées",
+		},
+		{
+			name:          "accents only",
+			normalization: addressNormalization{FoldAccents: true},
+			address:       "7 Avenue des Champs-Élysées",
+			want:          "7 Avenue des Champs-Elysees",
+		},
+		{
+			name:          "abbreviations without collapsing whitespace",
+			normalization: addressNormalization{ExpandAbbreviations: true},
+			address:       "7  R. de la Paix",
+			want:          "7  rue de la Paix",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.normalization.normalize(test.address); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestKeptAddress(t *testing.T) {
+	tests := []struct {
+		name          string
+		normalization addressNormalization
+		prior         types.String
+		current       types.String
+		want          types.String
+	}{
+		{
+			name:          "same canonical form",
+			normalization: defaultAddressNormalization,
+			prior:         types.StringValue("7 avenue des champs elysees"),
+			current:       types.StringValue("7 Avenue des Champs-Élysées"),
+			want:          types.StringValue("7 avenue des champs elysees"),
+		},
+		{
+			name:          "case is significant",
+			normalization: addressNormalization{CollapseWhitespace: true, FoldAccents: true},
+			prior:         types.StringValue("7 avenue des champs elysees"),
+			current:       types.StringValue("7 Avenue des Champs-Élysées"),
+			want:          types.StringValue("7 Avenue des Champs-Élysées"),
+		},
+		{
+			name:          "different address",
+			normalization: defaultAddressNormalization,
+			prior:         types.StringValue("7 avenue des champs elysees"),
+			current:       types.StringValue("8 Avenue des Champs-Élysées"),
+			want:          types.StringValue("8 Avenue des Champs-Élysées"),
+		},
+		{
+			name:          "imported",
+			normalization: defaultAddressNormalization,
+			prior:         types.StringNull(),
+			current:       types.StringValue("7 Avenue des Champs-Élysées"),
+			want:          types.StringValue("7 Avenue des Champs-Élysées"),
+		},
+		{
+			name:          "omitted by the API",
+			normalization: defaultAddressNormalization,
+			prior:         types.StringValue("7 avenue des champs elysees"),
+			current:       types.StringNull(),
+			want:          types.StringNull(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The prior value is read from state, with the type of the schema
+			normalization := test.normalization
+			prior, _ := newAddressType(&normalization).ValueFromString(context.Background(), test.prior)
+
+			got, diags := keptAddress(context.Background(), prior.(AddressValue), AddressValue{StringValue: test.current})
+			if diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+			if !got.StringValue.Equal(test.want) {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAddressSemanticEquals(t *testing.T) {
+	ctx := context.Background()
+	caseSensitive := addressNormalization{CollapseWhitespace: true, FoldAccents: true}
+
+	tests := []struct {
+		name          string
+		normalization *addressNormalization
+		a, b          string
+		want          bool
+	}{
+		{name: "default normalization", a: "7 av. des champs elysees", b: "7 Avenue des Champs-Élysées", want: true},
+		{name: "provider normalization", normalization: &caseSensitive, a: "7 avenue des champs elysees", b: "7 Avenue des Champs-Élysées"},
+		{name: "different address", a: "7 avenue des champs elysees", b: "8 avenue des champs elysees"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addressType := newAddressType(test.normalization)
+			a, _ := addressType.ValueFromString(ctx, types.StringValue(test.a))
+			b, _ := addressType.ValueFromString(ctx, types.StringValue(test.b))
+
+			got, diags := a.(AddressValue).StringSemanticEquals(ctx, b)
+			if diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+			if got != test.want {
+				t.Errorf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ basetypes.StringTypable = AddressType{}
+
+// AddressType is the type of house and store addresses. Values compare equal
+// to any address with the same canonical form, so that addresses rewritten by
+// the API or outside of Terraform never show as a diff.
+//
+// The normalization is shared with the provider, which sets it once configured:
+// schemas are built before the provider configuration is known. A nil
+// normalization, before the provider is configured, applies
+// defaultAddressNormalization.
+type AddressType struct {
+	basetypes.StringType
+	normalization *addressNormalization
+}
+
+// newAddressType returns the address type comparing addresses with the given
+// normalization.
+func newAddressType(normalization *addressNormalization) AddressType {
+	return AddressType{normalization: normalization}
+}
+
+func (t AddressType) Equal(o attr.Type) bool {
+	other, ok := o.(AddressType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t AddressType) String() string {
+	return "AddressType"
+}
+
+func (t AddressType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return AddressValue{StringValue: in, normalization: t.normalization}, nil
+}
+
+func (t AddressType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+	return stringValuable, nil
+}
+
+func (t AddressType) ValueType(ctx context.Context) attr.Value {
+	return AddressValue{normalization: t.normalization}
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = AddressValue{}
+
+// AddressValue is an address, equal to any address with the same canonical
+// form.
+type AddressValue struct {
+	basetypes.StringValue
+	normalization *addressNormalization
+}
+
+func (v AddressValue) Equal(o attr.Value) bool {
+	other, ok := o.(AddressValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v AddressValue) Type(ctx context.Context) attr.Type {
+	return AddressType{normalization: v.normalization}
+}
+
+func (v AddressValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(AddressValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable))
+		return false, diags
+	}
+	normalization := v.normalization
+	if normalization == nil {
+		normalization = newValue.normalization
+	}
+	if normalization == nil {
+		normalization = &defaultAddressNormalization
+	}
+	return normalization.equal(v.ValueString(), newValue.ValueString()), diags
+}
+
+// addressValue converts an API address to a Terraform value, null when
+// omitted.
+func addressValue(value string) AddressValue {
+	return AddressValue{StringValue: stringValue(value)}
+}
+
+// keptAddress returns the address kept in state after a refresh: the prior
+// address when it is semantically equal to the one read from the API, as the
+// framework does. Attributes derived from the address are computed from it.
+func keptAddress(ctx context.Context, prior, current AddressValue) (AddressValue, diag.Diagnostics) {
+	if prior.IsNull() || prior.IsUnknown() || current.IsNull() || current.IsUnknown() {
+		return current, nil
+	}
+	equal, diags := prior.StringSemanticEquals(ctx, current)
+	if !equal {
+		return current, diags
+	}
+	return prior, diags
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+// adoptExistingAttribute returns the adopt_existing schema attribute shared by
+// the city, house and store resources.
+func adoptExistingAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+		MarkdownDescription: "Take over an existing object with the same natural key on creation instead of creating a new one",
+	}
+}
+
+// adoptableCity returns the existing city with the given name, or nil if there
+// is none.
+func adoptableCity(c *client.SendoraCityClient, name string) (*City, error) {
+	cities, err := citiesNamed(c, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int{}
+	for _, city := range cities {
+		ids = append(ids, city.Id)
+	}
+	if err = checkAdoptable("cities", fmt.Sprintf("named %q", name), ids); err != nil || len(cities) == 0 {
+		return nil, err
+	}
+	return &cities[0], nil
+}
+
+// adoptableHouse returns the existing house of the city with the given
+// address, or nil if there is none.
+func adoptableHouse(c *client.SendoraCityClient, cityId int, address string) (*House, error) {
+	houses, err := listCityHouses(c, cityId)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []House{}
+	ids := []int{}
+	for _, house := range houses {
+		if house.Address == address {
+			matches = append(matches, house)
+			ids = append(ids, house.Id)
+		}
+	}
+	if err = checkAdoptable("houses", fmt.Sprintf("at %q in city %d", address, cityId), ids); err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	return &matches[0], nil
+}
+
+// adoptableStore returns the existing store of the city with the given name,
+// or nil if there is none.
+func adoptableStore(c *client.SendoraCityClient, cityId int, name string) (*Store, error) {
+	stores, err := listCityStores(c, cityId)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []Store{}
+	ids := []int{}
+	for _, store := range stores {
+		if store.Name == name {
+			matches = append(matches, store)
+			ids = append(ids, store.Id)
+		}
+	}
+	if err = checkAdoptable("stores", fmt.Sprintf("named %q in city %d", name, cityId), ids); err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	return &matches[0], nil
+}
+
+// checkAdoptable returns an error when several objects match the natural key,
+// as there is no way to tell which one should be adopted.
+func checkAdoptable(kinds, key string, ids []int) error {
+	if len(ids) <= 1 {
+		return nil
+	}
+
+	return fmt.Errorf("%d %s %s exist (ids %s), unable to choose one to adopt: import one of them instead",
+		len(ids), kinds, key, joinIds(ids))
+}
@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+// decodeResponse reads the response body and unmarshals it into target.
+func decodeResponse(res *http.Response, target any) error {
+	defer res.Body.Close()
+
+	responseBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read response body: %w", err)
+	}
+
+	if err = json.Unmarshal(responseBody, target); err != nil {
+		return fmt.Errorf("unable to unmarshal JSON body: %w", err)
+	}
+	return nil
+}
+
+// readCity returns the city with the given identifier, or nil if it does not exist.
+func readCity(c *client.SendoraCityClient, id string) (*City, error) {
+	res, err := c.DoRead(fmt.Sprintf("cities/%s", id))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, nil
+	}
+
+	city := &City{}
+	if err = decodeResponse(res, city); err != nil {
+		return nil, err
+	}
+	return city, nil
+}
+
+// objectExists reports whether the object at the given url exists.
+func objectExists(c *client.SendoraCityClient, url string) (bool, error) {
+	res, err := c.DoRead(url)
+	if err != nil {
+		return false, err
+	}
+	res.Body.Close()
+	return res.StatusCode != http.StatusNotFound, nil
+}
+
+// listCities returns the cities matching the given filters.
+func listCities(c *client.SendoraCityClient, filters map[string]string) ([]City, error) {
+	res, err := c.DoList("cities", filters)
+	if err != nil {
+		return nil, err
+	}
+
+	cities := []City{}
+	if err = decodeResponse(res, &cities); err != nil {
+		return nil, err
+	}
+	return cities, nil
+}
+
+// listHouses returns the houses matching the given filters.
+func listHouses(c *client.SendoraCityClient, filters map[string]string) ([]House, error) {
+	res, err := c.DoList("houses", filters)
+	if err != nil {
+		return nil, err
+	}
+
+	houses := []House{}
+	if err = decodeResponse(res, &houses); err != nil {
+		return nil, err
+	}
+	return houses, nil
+}
+
+// listStores returns the stores matching the given filters.
+func listStores(c *client.SendoraCityClient, filters map[string]string) ([]Store, error) {
+	res, err := c.DoList("stores", filters)
+	if err != nil {
+		return nil, err
+	}
+
+	stores := []Store{}
+	if err = decodeResponse(res, &stores); err != nil {
+		return nil, err
+	}
+	return stores, nil
+}
+
+// listStoreTypes returns the store types accepted by the API, or nil when the
+// API does not publish them.
+func listStoreTypes(c *client.SendoraCityClient) ([]string, error) {
+	res, err := c.DoList("storetypes", nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, nil
+	}
+
+	storeTypes := []string{}
+	if err = decodeResponse(res, &storeTypes); err != nil {
+		return nil, err
+	}
+	return storeTypes, nil
+}
+
+// listCityHouses returns the houses belonging to the given city. The result is
+// filtered client side as well, in case the API ignores the cityid filter.
+func listCityHouses(c *client.SendoraCityClient, cityId int) ([]House, error) {
+	houses, err := listHouses(c, map[string]string{"cityid": strconv.Itoa(cityId)})
+	if err != nil {
+		return nil, err
+	}
+
+	cityHouses := []House{}
+	for _, house := range houses {
+		if house.CityId == cityId {
+			cityHouses = append(cityHouses, house)
+		}
+	}
+	return cityHouses, nil
+}
+
+// listCityStores returns the stores belonging to the given city. The result is
+// filtered client side as well, in case the API ignores the cityid filter.
+func listCityStores(c *client.SendoraCityClient, cityId int) ([]Store, error) {
+	stores, err := listStores(c, map[string]string{"cityid": strconv.Itoa(cityId)})
+	if err != nil {
+		return nil, err
+	}
+
+	cityStores := []Store{}
+	for _, store := range stores {
+		if store.CityId == cityId {
+			cityStores = append(cityStores, store)
+		}
+	}
+	return cityStores, nil
+}
+
+// listCityChildren returns the houses and stores belonging to the given city,
+// fetching both lists concurrently.
+func listCityChildren(c *client.SendoraCityClient, cityId int) ([]House, []Store, error) {
+	var houses []House
+	var stores []Store
+	errs := runConcurrently(2, maxConcurrentRequests, func(i int) (err error) {
+		if i == 0 {
+			houses, err = listCityHouses(c, cityId)
+		} else {
+			stores, err = listCityStores(c, cityId)
+		}
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return houses, stores, nil
+}
+
+// createObject creates an object from body at the given url, and unmarshals
+// the created object into result.
+func createObject(c *client.SendoraCityClient, url string, body any, result any) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("unable to marshal JSON body: %w", err)
+	}
+
+	res, err := c.DoCreate(url, jsonBody)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(res, result)
+}
+
+// updateObject updates the object at the given url with body.
+func updateObject(c *client.SendoraCityClient, url string, body any) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("unable to marshal JSON body: %w", err)
+	}
+
+	res, err := c.DoUpdate(url, jsonBody)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// deleteObject deletes the object at the given url. Objects that no longer
+// exist are considered deleted.
+func deleteObject(c *client.SendoraCityClient, url string) error {
+	res, err := c.DoDelete(url)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
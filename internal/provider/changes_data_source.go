@@ -0,0 +1,351 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+var _ datasource.DataSource = &ChangesDataSource{}
+
+func NewChangesDataSource() datasource.DataSource {
+	return &ChangesDataSource{}
+}
+
+type ChangesDataSource struct {
+	client      *client.SendoraCityClient
+	nameAffixes nameAffixes
+}
+
+type ChangesDataSourceModel struct {
+	Since        types.String             `tfsdk:"since"`
+	EntityTypes  []types.String           `tfsdk:"entity_types"`
+	Cities       []ChangesDataSourceCity  `tfsdk:"cities"`
+	Houses       []ChangesDataSourceHouse `tfsdk:"houses"`
+	Stores       []ChangesDataSourceStore `tfsdk:"stores"`
+	MaxTimestamp types.String             `tfsdk:"max_timestamp"`
+}
+
+type ChangesDataSourceCity struct {
+	Id        types.Int64  `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Touristic types.Bool   `tfsdk:"touristic"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+type ChangesDataSourceHouse struct {
+	Id          types.Int64  `tfsdk:"id"`
+	CityId      types.Int64  `tfsdk:"city_id"`
+	Address     types.String `tfsdk:"address"`
+	Inhabitants types.Int64  `tfsdk:"inhabitants"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+}
+
+type ChangesDataSourceStore struct {
+	Id        types.Int64  `tfsdk:"id"`
+	CityId    types.Int64  `tfsdk:"city_id"`
+	Address   types.String `tfsdk:"address"`
+	Name      types.String `tfsdk:"name"`
+	Type      types.String `tfsdk:"type"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+func (d *ChangesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_changes"
+}
+
+func (d *ChangesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Cities, houses and stores created after a given timestamp",
+
+		Attributes: map[string]schema.Attribute{
+			"since": schema.StringAttribute{
+				MarkdownDescription: "Only return objects created strictly after this timestamp (RFC3339)",
+				Required:            true,
+			},
+			"entity_types": schema.SetAttribute{
+				MarkdownDescription: "Entity types to look for, among `city`, `house` and `store`. Defaults to all of them",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.OneOf("city", "house", "store")),
+				},
+			},
+			"cities": schema.ListNestedAttribute{
+				MarkdownDescription: "Cities created after `since`",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							MarkdownDescription: "City identifier",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "City name",
+							Computed:            true,
+						},
+						"touristic": schema.BoolAttribute{
+							MarkdownDescription: "Whether the city is touristic or not",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "City creation timestamp (RFC3339)",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"houses": schema.ListNestedAttribute{
+				MarkdownDescription: "Houses created after `since`",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							MarkdownDescription: "House identifier",
+							Computed:            true,
+						},
+						"city_id": schema.Int64Attribute{
+							MarkdownDescription: "House city identifier",
+							Computed:            true,
+						},
+						"address": schema.StringAttribute{
+							MarkdownDescription: "House address",
+							Computed:            true,
+						},
+						"inhabitants": schema.Int64Attribute{
+							MarkdownDescription: "House inhabitants count",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "House creation timestamp (RFC3339)",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"stores": schema.ListNestedAttribute{
+				MarkdownDescription: "Stores created after `since`",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							MarkdownDescription: "Store identifier",
+							Computed:            true,
+						},
+						"city_id": schema.Int64Attribute{
+							MarkdownDescription: "Store city identifier",
+							Computed:            true,
+						},
+						"address": schema.StringAttribute{
+							MarkdownDescription: "Store address",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Store name",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Store type",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "Store creation timestamp (RFC3339)",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"max_timestamp": schema.StringAttribute{
+				MarkdownDescription: "Most recent creation timestamp seen (RFC3339), to be used as the next `since` value. " +
+					"Equals `since` when nothing changed",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *ChangesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
+				"Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+	d.nameAffixes = providerData.NameAffixes
+}
+
+func (d *ChangesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ChangesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	since, err := time.Parse(time.RFC3339, data.Since.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("since"), "Invalid Timestamp",
+			fmt.Sprintf("Unable to parse since as an RFC3339 timestamp, got error: %s", err))
+		return
+	}
+
+	entityTypes := map[string]bool{"city": true, "house": true, "store": true}
+	if data.EntityTypes != nil {
+		entityTypes = map[string]bool{}
+		for _, entityType := range data.EntityTypes {
+			entityTypes[entityType.ValueString()] = true
+		}
+	}
+
+	// The since filter is sent to the API in the local time of the database,
+	// but results are filtered client side as well since older API versions
+	// ignore it, or reject it in which case everything is listed.
+	filters := map[string]string{"since": apiTimestamp(since)}
+
+	var cities []City
+	var houses []House
+	var stores []Store
+	errs := runConcurrently(3, maxConcurrentRequests, func(i int) (err error) {
+		switch {
+		case i == 0 && entityTypes["city"]:
+			cities, err = listWithFallback(ctx, "cities", filters, func(filters map[string]string) ([]City, error) {
+				return listCities(d.client, filters)
+			})
+		case i == 1 && entityTypes["house"]:
+			houses, err = listWithFallback(ctx, "houses", filters, func(filters map[string]string) ([]House, error) {
+				return listHouses(d.client, filters)
+			})
+		case i == 2 && entityTypes["store"]:
+			stores, err = listWithFallback(ctx, "stores", filters, func(filters map[string]string) ([]Store, error) {
+				return listStores(d.client, filters)
+			})
+		}
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to list changes, got error: %s", err))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Objects with an unparsable timestamp cannot be placed relative to since
+	// and max_timestamp, returning them would repeat them on every poll. They
+	// are excluded with a warning rather than silently.
+	maxTimestamp := since
+	var unparsable []string
+	changedAfter := func(kind string, id int, timestamp string) bool {
+		t, err := parseTimestamp(timestamp)
+		if err != nil {
+			unparsable = append(unparsable, fmt.Sprintf("%s %d (%q)", kind, id, timestamp))
+			return false
+		}
+		if !t.After(since) {
+			return false
+		}
+		if t.After(maxTimestamp) {
+			maxTimestamp = t
+		}
+		return true
+	}
+
+	sort.SliceStable(cities, func(i, j int) bool {
+		return createdBefore(cities[i].Timestamp, cities[i].Id, cities[j].Timestamp, cities[j].Id)
+	})
+	sort.SliceStable(houses, func(i, j int) bool {
+		return createdBefore(houses[i].Timestamp, houses[i].Id, houses[j].Timestamp, houses[j].Id)
+	})
+	sort.SliceStable(stores, func(i, j int) bool {
+		return createdBefore(stores[i].Timestamp, stores[i].Id, stores[j].Timestamp, stores[j].Id)
+	})
+
+	data.Cities = []ChangesDataSourceCity{}
+	for _, city := range cities {
+		if !changedAfter("city", city.Id, city.Timestamp) {
+			continue
+		}
+		data.Cities = append(data.Cities, ChangesDataSourceCity{
+			Id:        types.Int64Value(int64(city.Id)),
+			Name:      d.nameAffixes.configNameValue(types.StringValue(city.Name)),
+			Touristic: boolValue(city.Touristic),
+			CreatedAt: createdAtValue(city.Timestamp),
+		})
+	}
+
+	data.Houses = []ChangesDataSourceHouse{}
+	for _, house := range houses {
+		if !changedAfter("house", house.Id, house.Timestamp) {
+			continue
+		}
+		data.Houses = append(data.Houses, ChangesDataSourceHouse{
+			Id:          types.Int64Value(int64(house.Id)),
+			CityId:      types.Int64Value(int64(house.CityId)),
+			Address:     types.StringValue(house.Address),
+			Inhabitants: int64Value(house.Inhabitants),
+			CreatedAt:   createdAtValue(house.Timestamp),
+		})
+	}
+
+	data.Stores = []ChangesDataSourceStore{}
+	for _, store := range stores {
+		if !changedAfter("store", store.Id, store.Timestamp) {
+			continue
+		}
+		data.Stores = append(data.Stores, ChangesDataSourceStore{
+			Id:        types.Int64Value(int64(store.Id)),
+			CityId:    types.Int64Value(int64(store.CityId)),
+			Address:   types.StringValue(store.Address),
+			Name:      d.nameAffixes.configNameValue(types.StringValue(store.Name)),
+			Type:      types.StringValue(store.Type),
+			CreatedAt: createdAtValue(store.Timestamp),
+		})
+	}
+
+	data.MaxTimestamp = types.StringValue(maxTimestamp.UTC().Format(time.RFC3339Nano))
+	if len(unparsable) > 0 {
+		resp.Diagnostics.AddWarning("Unparsable Timestamps",
+			fmt.Sprintf("Unable to parse the creation timestamp of %d object(s), excluded from the changes: %s",
+				len(unparsable), strings.Join(unparsable, ", ")))
+	}
+
+	tflog.Trace(ctx, "read changes from a data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// listWithFallback lists objects with the given filters, and lists every
+// object when the API rejects the filters.
+func listWithFallback[T any](ctx context.Context, kind string, filters map[string]string, list func(map[string]string) ([]T, error)) ([]T, error) {
+	objects, err := list(filters)
+	if err == nil {
+		return objects, nil
+	}
+
+	tflog.Debug(ctx, "listing without filters", map[string]any{"kind": kind, "error": err.Error()})
+	return list(nil)
+}
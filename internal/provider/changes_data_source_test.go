@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccChangesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccChangesDataSourceConfig("data-changes-test"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.sendoracity_changes.test", "cities.0.id"),
+					resource.TestCheckResourceAttrSet("data.sendoracity_changes.test", "max_timestamp"),
+					resource.TestCheckResourceAttr("data.sendoracity_changes.test", "houses.#", "0"),
+					resource.TestCheckResourceAttr("data.sendoracity_changes.test", "stores.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccChangesDataSourceConfig(name string) string {
+	return fmt.Sprintf(`
+%s
+
+data "sendoracity_changes" "test" {
+	since        = "2000-01-01T00:00:00Z"
+	entity_types = ["city"]
+
+	depends_on = [sendoracity_city.test]
+}
+`, testAccCityResourceConfig(name))
+}
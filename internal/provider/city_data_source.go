@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"strconv"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -21,14 +21,37 @@ func NewCityDataSource() datasource.DataSource {
 }
 
 type CityDataSource struct {
-	client *client.SendoraCityClient
-	url    string
+	client      *client.SendoraCityClient
+	url         string
+	nameAffixes nameAffixes
 }
 
 type CityDataSourceModel struct {
-	Id        types.String `tfsdk:"id"`
+	Id            types.Int64                `tfsdk:"id"`
+	Name          types.String               `tfsdk:"name"`
+	Touristic     types.Bool                 `tfsdk:"touristic"`
+	IncludeHouses types.Bool                 `tfsdk:"include_houses"`
+	IncludeStores types.Bool                 `tfsdk:"include_stores"`
+	Houses        []CityDataSourceHouseModel `tfsdk:"houses"`
+	Stores        []CityDataSourceStoreModel `tfsdk:"stores"`
+	Population    types.Int64                `tfsdk:"population"`
+	StoreCount    types.Int64                `tfsdk:"store_count"`
+	CreatedAt     types.String               `tfsdk:"created_at"`
+}
+
+type CityDataSourceHouseModel struct {
+	Id          types.Int64  `tfsdk:"id"`
+	Address     types.String `tfsdk:"address"`
+	Inhabitants types.Int64  `tfsdk:"inhabitants"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+}
+
+type CityDataSourceStoreModel struct {
+	Id        types.Int64  `tfsdk:"id"`
+	Address   types.String `tfsdk:"address"`
 	Name      types.String `tfsdk:"name"`
-	Touristic types.Bool   `tfsdk:"touristic"`
+	Type      types.String `tfsdk:"type"`
+	CreatedAt types.String `tfsdk:"created_at"`
 }
 
 func (d *CityDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -40,20 +63,94 @@ func (d *CityDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 		MarkdownDescription: "City data source",
 
 		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
+			"id": schema.Int64Attribute{
 				MarkdownDescription: "City identifier",
 				Optional:            true,
 				Computed:            true,
+				Validators:          idValidators(),
 			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "City name",
 				Optional:            true,
 				Computed:            true,
+				Validators:          textValidators(),
 			},
 			"touristic": schema.BoolAttribute{
 				MarkdownDescription: "Whether the city is touristic or not",
 				Computed:            true,
 			},
+			"include_houses": schema.BoolAttribute{
+				MarkdownDescription: "Whether to populate the city houses",
+				Optional:            true,
+			},
+			"include_stores": schema.BoolAttribute{
+				MarkdownDescription: "Whether to populate the city stores",
+				Optional:            true,
+			},
+			"houses": schema.ListNestedAttribute{
+				MarkdownDescription: "City houses, populated when `include_houses` is set",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							MarkdownDescription: "House identifier",
+							Computed:            true,
+						},
+						"address": schema.StringAttribute{
+							MarkdownDescription: "House address",
+							Computed:            true,
+						},
+						"inhabitants": schema.Int64Attribute{
+							MarkdownDescription: "House inhabitants count",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "House creation timestamp (RFC3339)",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"stores": schema.ListNestedAttribute{
+				MarkdownDescription: "City stores, populated when `include_stores` is set",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							MarkdownDescription: "Store identifier",
+							Computed:            true,
+						},
+						"address": schema.StringAttribute{
+							MarkdownDescription: "Store address",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Store name",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Store type",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "Store creation timestamp (RFC3339)",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"population": schema.Int64Attribute{
+				MarkdownDescription: "Sum of the inhabitants of the city houses",
+				Computed:            true,
+			},
+			"store_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of stores in the city",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "City creation timestamp (RFC3339)",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -63,18 +160,19 @@ func (d *CityDataSource) Configure(ctx context.Context, req datasource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SendoraCityClient)
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.SendoraCityClient, got: %T."+
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
 				"Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = providerData.Client
+	d.nameAffixes = providerData.NameAffixes
 	d.url = "cities"
 }
 
@@ -89,7 +187,7 @@ func (d *CityDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 
 	var city City
 	if !data.Id.IsNull() {
-		res, err := d.client.DoRead(fmt.Sprintf("%s/%s", d.url, data.Id.ValueString()))
+		res, err := d.client.DoRead(fmt.Sprintf("%s/%d", d.url, data.Id.ValueInt64()))
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error",
 				fmt.Sprintf("Unable to read city, got error: %s", err))
@@ -110,7 +208,7 @@ func (d *CityDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 			return
 		}
 	} else if !data.Name.IsNull() {
-		res, err := d.client.DoList(d.url, map[string]string{"name": data.Name.ValueString()})
+		res, err := d.client.DoList(d.url, map[string]string{"name": d.nameAffixes.apiName(data.Name.ValueString())})
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error",
 				fmt.Sprintf("Unable to read city, got error: %s", err))
@@ -131,6 +229,16 @@ func (d *CityDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 				fmt.Sprintf("Unable to unmarshal JSON body for city, got error: %s", err))
 			return
 		}
+		if len(cities) == 0 {
+			resp.Diagnostics.AddError("Not Found",
+				fmt.Sprintf("No city found with name %s", data.Name.ValueString()))
+			return
+		}
+
+		// Names are not unique, the oldest matching city wins
+		sort.SliceStable(cities, func(i, j int) bool {
+			return createdBefore(cities[i].Timestamp, cities[i].Id, cities[j].Timestamp, cities[j].Id)
+		})
 		city = cities[0]
 	} else {
 		resp.Diagnostics.AddError("Invalid configuration",
@@ -138,9 +246,58 @@ func (d *CityDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	data.Id = types.StringValue(strconv.Itoa(city.Id))
-	data.Name = types.StringValue(city.Name)
-	data.Touristic = types.BoolValue(*city.Touristic)
+	data.Id = types.Int64Value(int64(city.Id))
+	data.Name = d.nameAffixes.configNameValue(types.StringValue(city.Name))
+	data.Touristic = boolValue(city.Touristic)
+	data.CreatedAt = createdAtValue(city.Timestamp)
+
+	houses, stores, err := listCityChildren(d.client, city.Id)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to list city %d children, got error: %s", city.Id, err))
+		return
+	}
+
+	population := 0
+	for _, house := range houses {
+		population += valueOf(house.Inhabitants)
+	}
+	data.Population = types.Int64Value(int64(population))
+	data.StoreCount = types.Int64Value(int64(len(stores)))
+
+	sort.SliceStable(houses, func(i, j int) bool {
+		return createdBefore(houses[i].Timestamp, houses[i].Id, houses[j].Timestamp, houses[j].Id)
+	})
+	sort.SliceStable(stores, func(i, j int) bool {
+		return createdBefore(stores[i].Timestamp, stores[i].Id, stores[j].Timestamp, stores[j].Id)
+	})
+
+	data.Houses = nil
+	if data.IncludeHouses.ValueBool() {
+		data.Houses = []CityDataSourceHouseModel{}
+		for _, house := range houses {
+			data.Houses = append(data.Houses, CityDataSourceHouseModel{
+				Id:          types.Int64Value(int64(house.Id)),
+				Address:     types.StringValue(house.Address),
+				Inhabitants: int64Value(house.Inhabitants),
+				CreatedAt:   createdAtValue(house.Timestamp),
+			})
+		}
+	}
+
+	data.Stores = nil
+	if data.IncludeStores.ValueBool() {
+		data.Stores = []CityDataSourceStoreModel{}
+		for _, store := range stores {
+			data.Stores = append(data.Stores, CityDataSourceStoreModel{
+				Id:        types.Int64Value(int64(store.Id)),
+				Address:   types.StringValue(store.Address),
+				Name:      d.nameAffixes.configNameValue(types.StringValue(store.Name)),
+				Type:      types.StringValue(store.Type),
+				CreatedAt: createdAtValue(store.Timestamp),
+			})
+		}
+	}
 
 	tflog.Trace(ctx, "read city from a data source")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -17,6 +17,7 @@ func TestAccCityDataSource(t *testing.T) {
 				Config: testAccCityIdDataSourceConfig("data-city-test-id"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("data.sendoracity_city.test", "id"),
+					resource.TestCheckResourceAttrSet("data.sendoracity_city.test", "created_at"),
 					resource.TestCheckResourceAttr("data.sendoracity_city.test", "name", "data-city-test-id"),
 					resource.TestCheckResourceAttr("data.sendoracity_city.test", "touristic", "false"),
 				),
@@ -25,10 +26,22 @@ func TestAccCityDataSource(t *testing.T) {
 				Config: testAccCityNameDataSourceConfig("data-city-test-name"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("data.sendoracity_city.test", "id"),
+					resource.TestCheckResourceAttrSet("data.sendoracity_city.test", "created_at"),
 					resource.TestCheckResourceAttr("data.sendoracity_city.test", "name", "data-city-test-name"),
 					resource.TestCheckResourceAttr("data.sendoracity_city.test", "touristic", "false"),
 				),
 			},
+			{
+				Config: testAccCityChildrenDataSourceConfig("data-city-test-children"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sendoracity_city.test", "population", "4"),
+					resource.TestCheckResourceAttr("data.sendoracity_city.test", "store_count", "1"),
+					resource.TestCheckResourceAttr("data.sendoracity_city.test", "houses.#", "1"),
+					resource.TestCheckResourceAttr("data.sendoracity_city.test", "houses.0.address", "data-city-test-house"),
+					resource.TestCheckResourceAttr("data.sendoracity_city.test", "stores.#", "1"),
+					resource.TestCheckResourceAttr("data.sendoracity_city.test", "stores.0.name", "Store 1"),
+				),
+			},
 		},
 	})
 }
@@ -52,3 +65,30 @@ data "sendoracity_city" "test" {
 }
 `, testAccCityResourceConfig(name))
 }
+
+func testAccCityChildrenDataSourceConfig(name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "sendoracity_house" "test" {
+  city_id     = sendoracity_city.test.id
+  address     = "data-city-test-house"
+  inhabitants = 4
+}
+
+resource "sendoracity_store" "test" {
+  city_id = sendoracity_city.test.id
+  address = "data-city-test-store"
+  name    = "Store 1"
+  type    = "Other"
+}
+
+data "sendoracity_city" "test" {
+	id             = sendoracity_city.test.id
+	include_houses = true
+	include_stores = true
+
+	depends_on = [sendoracity_house.test, sendoracity_store.test]
+}
+`, testAccCityResourceConfig(name))
+}
@@ -0,0 +1,434 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+var _ resource.Resource = &CityMembershipResource{}
+var _ resource.ResourceWithImportState = &CityMembershipResource{}
+var _ resource.ResourceWithUpgradeState = &CityMembershipResource{}
+
+func NewCityMembershipResource() resource.Resource {
+	return &CityMembershipResource{}
+}
+
+type CityMembershipResource struct {
+	client *client.SendoraCityClient
+}
+
+type CityMembershipResourceModel struct {
+	Id                types.Int64 `tfsdk:"id"`
+	CityId            types.Int64 `tfsdk:"city_id"`
+	Authoritative     types.Bool  `tfsdk:"authoritative"`
+	HouseIds          types.Set   `tfsdk:"house_ids"`
+	StoreIds          types.Set   `tfsdk:"store_ids"`
+	UnmanagedHouseIds types.Set   `tfsdk:"unmanaged_house_ids"`
+	UnmanagedStoreIds types.Set   `tfsdk:"unmanaged_store_ids"`
+}
+
+func (r *CityMembershipResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_city_membership"
+}
+
+func (r *CityMembershipResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	emptySet := types.SetValueMust(types.Int64Type, []attr.Value{})
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Houses and stores belonging to a city. When authoritative, houses and stores " +
+			"of the city that are not listed are deleted",
+		Version: 1,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Membership identifier, same as the city identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"city_id": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "City identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: idValidators(),
+			},
+			"authoritative": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Delete the houses and stores of the city that are not listed",
+			},
+			"house_ids": schema.SetAttribute{
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.Int64Type,
+				Default:             setdefault.StaticValue(emptySet),
+				MarkdownDescription: "Identifiers of the houses belonging to the city",
+				Validators: []validator.Set{
+					setvalidator.ValueInt64sAre(idValidators()...),
+				},
+			},
+			"store_ids": schema.SetAttribute{
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.Int64Type,
+				Default:             setdefault.StaticValue(emptySet),
+				MarkdownDescription: "Identifiers of the stores belonging to the city",
+				Validators: []validator.Set{
+					setvalidator.ValueInt64sAre(idValidators()...),
+				},
+			},
+			"unmanaged_house_ids": schema.SetAttribute{
+				Computed:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "Identifiers of the houses of the city that are not listed in `house_ids`",
+				PlanModifiers: []planmodifier.Set{
+					unmanagedIdsPlanModifier{managed: "house_ids"},
+				},
+			},
+			"unmanaged_store_ids": schema.SetAttribute{
+				Computed:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "Identifiers of the stores of the city that are not listed in `store_ids`",
+				PlanModifiers: []planmodifier.Set{
+					unmanagedIdsPlanModifier{managed: "store_ids"},
+				},
+			},
+		},
+	}
+}
+
+func (r *CityMembershipResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: idsToInt64StateUpgrader([]string{"id", "city_id", "house_ids", "store_ids", "unmanaged_house_ids", "unmanaged_store_ids"}, nil),
+	}
+}
+
+func (r *CityMembershipResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
+				"Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+}
+
+func (r *CityMembershipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *CityMembershipResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a city membership resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CityMembershipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CityMembershipResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Authoritative.IsNull() {
+		data.Authoritative = types.BoolValue(false)
+	}
+
+	city, err := readCity(r.client, strconv.FormatInt(data.CityId.ValueInt64(), 10))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to read city, got error: %s", err))
+		return
+	}
+	if city == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	houses, stores, err := listCityChildren(r.client, city.Id)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to list city %d children, got error: %s", city.Id, err))
+		return
+	}
+
+	houseIds, storeIds := membershipIds(houses, stores)
+	managedHouseIds, diags := setToIds(ctx, data.HouseIds)
+	resp.Diagnostics.Append(diags...)
+	managedStoreIds, diags := setToIds(ctx, data.StoreIds)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	unmanagedHouseIds := difference(houseIds, managedHouseIds)
+	unmanagedStoreIds := difference(storeIds, managedStoreIds)
+
+	// An authoritative membership reports every house and store of the city,
+	// so that unmanaged ones show up as drift and get deleted on the next apply.
+	if data.Authoritative.ValueBool() {
+		data.HouseIds = idsToSet(houseIds)
+		data.StoreIds = idsToSet(storeIds)
+	} else {
+		data.HouseIds = idsToSet(intersection(managedHouseIds, houseIds))
+		data.StoreIds = idsToSet(intersection(managedStoreIds, storeIds))
+		if len(unmanagedHouseIds)+len(unmanagedStoreIds) > 0 {
+			resp.Diagnostics.AddWarning("Unmanaged City Members",
+				fmt.Sprintf("City %d has houses [%s] and stores [%s] that are not part of the membership.",
+					city.Id, joinIds(unmanagedHouseIds), joinIds(unmanagedStoreIds)))
+		}
+	}
+	data.UnmanagedHouseIds = idsToSet(unmanagedHouseIds)
+	data.UnmanagedStoreIds = idsToSet(unmanagedStoreIds)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CityMembershipResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *CityMembershipResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CityMembershipResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Removing the membership leaves the houses and stores of the city untouched
+}
+
+func (r *CityMembershipResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	cityId, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import Identifier",
+			fmt.Sprintf("Expected a city identifier, got: %s", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), cityId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("city_id"), cityId)...)
+}
+
+// apply checks that every listed house and store belongs to the city and, for
+// authoritative memberships, deletes the unlisted ones.
+func (r *CityMembershipResource) apply(ctx context.Context, data *CityMembershipResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	cityId := int(data.CityId.ValueInt64())
+	houses, stores, err := listCityChildren(r.client, cityId)
+	if err != nil {
+		diags.AddError("Client Error",
+			fmt.Sprintf("Unable to list city %d children, got error: %s", cityId, err))
+		return diags
+	}
+
+	houseIds, storeIds := membershipIds(houses, stores)
+	managedHouseIds, d := setToIds(ctx, data.HouseIds)
+	diags.Append(d...)
+	managedStoreIds, d := setToIds(ctx, data.StoreIds)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, id := range difference(managedHouseIds, houseIds) {
+		diags.AddAttributeError(path.Root("house_ids"), "Invalid Membership",
+			fmt.Sprintf("House %d does not belong to city %d", id, cityId))
+	}
+	for _, id := range difference(managedStoreIds, storeIds) {
+		diags.AddAttributeError(path.Root("store_ids"), "Invalid Membership",
+			fmt.Sprintf("Store %d does not belong to city %d", id, cityId))
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	unmanagedHouseIds := difference(houseIds, managedHouseIds)
+	unmanagedStoreIds := difference(storeIds, managedStoreIds)
+
+	if data.Authoritative.ValueBool() {
+		urls := []string{}
+		for _, id := range unmanagedHouseIds {
+			urls = append(urls, fmt.Sprintf("houses/%d", id))
+		}
+		for _, id := range unmanagedStoreIds {
+			urls = append(urls, fmt.Sprintf("stores/%d", id))
+		}
+
+		errs := runConcurrently(len(urls), maxConcurrentRequests, func(i int) error {
+			return retry(ctx, func() error {
+				return deleteObject(r.client, urls[i])
+			})
+		})
+		for i, err := range errs {
+			if err != nil {
+				diags.AddError("Client Error",
+					fmt.Sprintf("Unable to delete unmanaged %s of city %d, got error: %s", urls[i], cityId, err))
+			}
+		}
+		if diags.HasError() {
+			return diags
+		}
+		if len(urls) > 0 {
+			tflog.Debug(ctx, "deleted unmanaged city members", map[string]any{"city_id": cityId, "members": urls})
+		}
+
+		unmanagedHouseIds = []int{}
+		unmanagedStoreIds = []int{}
+	}
+
+	data.Id = types.Int64Value(int64(cityId))
+	data.UnmanagedHouseIds = idsToSet(unmanagedHouseIds)
+	data.UnmanagedStoreIds = idsToSet(unmanagedStoreIds)
+	return diags
+}
+
+var _ planmodifier.Set = unmanagedIdsPlanModifier{}
+
+// unmanagedIdsPlanModifier keeps the unmanaged identifiers of the state while
+// the city, the managed identifiers and authoritative do not change, as they
+// are only computed again by the next apply.
+type unmanagedIdsPlanModifier struct {
+	managed string
+}
+
+func (m unmanagedIdsPlanModifier) Description(ctx context.Context) string {
+	return fmt.Sprintf("Keeps the value in state unless city_id, %s or authoritative change.", m.managed)
+}
+
+func (m unmanagedIdsPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Keeps the value in state unless `city_id`, `%s` or `authoritative` change.", m.managed)
+}
+
+func (m unmanagedIdsPlanModifier) PlanModifySet(ctx context.Context, req planmodifier.SetRequest, resp *planmodifier.SetResponse) {
+	if req.StateValue.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+
+	for _, attribute := range []string{"city_id", m.managed, "authoritative"} {
+		var planned, prior attr.Value
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(attribute), &planned)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(attribute), &prior)...)
+		if resp.Diagnostics.HasError() || !planned.Equal(prior) {
+			return
+		}
+	}
+
+	resp.PlanValue = req.StateValue
+}
+
+// membershipIds returns the sorted identifiers of the given houses and stores.
+func membershipIds(houses []House, stores []Store) ([]int, []int) {
+	houseIds := []int{}
+	for _, house := range houses {
+		houseIds = append(houseIds, house.Id)
+	}
+	storeIds := []int{}
+	for _, store := range stores {
+		storeIds = append(storeIds, store.Id)
+	}
+	sort.Ints(houseIds)
+	sort.Ints(storeIds)
+	return houseIds, storeIds
+}
+
+// setToIds returns the elements of a set of identifiers.
+func setToIds(ctx context.Context, set types.Set) ([]int, diag.Diagnostics) {
+	ids := []int{}
+	if set.IsNull() || set.IsUnknown() {
+		return ids, nil
+	}
+	values := []int64{}
+	diags := set.ElementsAs(ctx, &values, false)
+	for _, value := range values {
+		ids = append(ids, int(value))
+	}
+	return ids, diags
+}
+
+// idsToSet returns a set holding the given identifiers.
+func idsToSet(ids []int) types.Set {
+	elements := []attr.Value{}
+	for _, id := range ids {
+		elements = append(elements, types.Int64Value(int64(id)))
+	}
+	return types.SetValueMust(types.Int64Type, elements)
+}
+
+// difference returns the identifiers of a that are not in b.
+func difference(a, b []int) []int {
+	inB := make(map[int]bool)
+	for _, id := range b {
+		inB[id] = true
+	}
+	result := []int{}
+	for _, id := range a {
+		if !inB[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// intersection returns the identifiers of a that are also in b.
+func intersection(a, b []int) []int {
+	inB := make(map[int]bool)
+	for _, id := range b {
+		inB[id] = true
+	}
+	result := []int{}
+	for _, id := range a {
+		if inB[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCityMembershipResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, then add a house outside of Terraform
+			{
+				Config: testAccCityMembershipResourceConfig("city-membership-test", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("sendoracity_city_membership.test", "id", "sendoracity_city.test", "id"),
+					resource.TestCheckResourceAttr("sendoracity_city_membership.test", "house_ids.#", "1"),
+					resource.TestCheckResourceAttr("sendoracity_city_membership.test", "unmanaged_house_ids.#", "0"),
+					testAccCreateUnmanagedHouse("sendoracity_city.test", "city-membership-test-unmanaged"),
+				),
+			},
+			// Non authoritative membership reports the unmanaged house
+			{
+				Config: testAccCityMembershipResourceConfig("city-membership-test", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_city_membership.test", "unmanaged_house_ids.#", "1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "sendoracity_city_membership.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Authoritative membership deletes the unmanaged house
+			{
+				Config: testAccCityMembershipResourceConfig("city-membership-test", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_city_membership.test", "house_ids.#", "1"),
+					resource.TestCheckResourceAttr("sendoracity_city_membership.test", "unmanaged_house_ids.#", "0"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccCityMembershipResourceConfig(cityName string, authoritative bool) string {
+	return fmt.Sprintf(`
+%s
+
+resource "sendoracity_house" "test" {
+  city_id     = sendoracity_city.test.id
+  address     = "city-membership-test-managed"
+  inhabitants = 2
+}
+
+resource "sendoracity_city_membership" "test" {
+  city_id       = sendoracity_city.test.id
+  authoritative = %t
+  house_ids     = [sendoracity_house.test.id]
+}
+`, testAccCityResourceForceDestroyConfig(cityName), authoritative)
+}
+
+func TestUnmanagedIdsPlanModifier(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	NewCityMembershipResource().Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	model := func(cityId int64, houseIds []int, authoritative bool, unmanaged types.Set) CityMembershipResourceModel {
+		return CityMembershipResourceModel{
+			Id:                types.Int64Value(cityId),
+			CityId:            types.Int64Value(cityId),
+			Authoritative:     types.BoolValue(authoritative),
+			HouseIds:          idsToSet(houseIds),
+			StoreIds:          idsToSet([]int{}),
+			UnmanagedHouseIds: unmanaged,
+			UnmanagedStoreIds: idsToSet([]int{}),
+		}
+	}
+	unknown := types.SetUnknown(types.Int64Type)
+	state := model(1, []int{10}, false, idsToSet([]int{11}))
+
+	tests := []struct {
+		name string
+		plan CityMembershipResourceModel
+		want types.Set
+	}{
+		{name: "unchanged", plan: model(1, []int{10}, false, unknown), want: idsToSet([]int{11})},
+		{name: "managed ids changed", plan: model(1, []int{10, 11}, false, unknown), want: unknown},
+		{name: "city changed", plan: model(2, []int{10}, false, unknown), want: unknown},
+		{name: "authoritative changed", plan: model(1, []int{10}, true, unknown), want: unknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan := tfsdk.Plan{Schema: schemaResp.Schema}
+			prior := tfsdk.State{Schema: schemaResp.Schema}
+			if diags := plan.Set(ctx, test.plan); diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+			if diags := prior.Set(ctx, state); diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+
+			req := planmodifier.SetRequest{
+				Plan:       plan,
+				State:      prior,
+				PlanValue:  test.plan.UnmanagedHouseIds,
+				StateValue: state.UnmanagedHouseIds,
+			}
+			resp := &planmodifier.SetResponse{PlanValue: req.PlanValue}
+			unmanagedIdsPlanModifier{managed: "house_ids"}.PlanModifySet(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+			}
+			if !resp.PlanValue.Equal(test.want) {
+				t.Errorf("got %s, want %s", resp.PlanValue, test.want)
+			}
+		})
+	}
+}
@@ -7,10 +7,14 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -20,20 +24,33 @@ import (
 
 var _ resource.Resource = &CityResource{}
 var _ resource.ResourceWithImportState = &CityResource{}
+var _ resource.ResourceWithUpgradeState = &CityResource{}
+var _ resource.ResourceWithModifyPlan = &CityResource{}
+var _ resource.ResourceWithValidateConfig = &CityResource{}
 
 func NewCityResource() resource.Resource {
 	return &CityResource{}
 }
 
 type CityResource struct {
-	client *client.SendoraCityClient
-	url    string
+	client                 *client.SendoraCityClient
+	url                    string
+	maxInhabitants         int64
+	storeTypes             *storeTypeCatalog
+	enforceUniqueCityNames bool
+	nameAffixes            nameAffixes
 }
 
 type CityResourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Name      types.String `tfsdk:"name"`
-	Touristic types.Bool   `tfsdk:"touristic"`
+	Id                 types.Int64  `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	Touristic          types.Bool   `tfsdk:"touristic"`
+	CreatedAt          types.String `tfsdk:"created_at"`
+	ForceDestroy       types.Bool   `tfsdk:"force_destroy"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+	AdoptExisting      types.Bool   `tfsdk:"adopt_existing"`
+	House              types.Set    `tfsdk:"house"`
+	Store              types.Set    `tfsdk:"store"`
 }
 
 func (r *CityResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -43,11 +60,19 @@ func (r *CityResource) Metadata(ctx context.Context, req resource.MetadataReques
 func (r *CityResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "City resource",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
+			"id": schema.Int64Attribute{
 				Computed:            true,
 				MarkdownDescription: "City identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "City creation timestamp (RFC3339)",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -55,32 +80,65 @@ func (r *CityResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 			"name": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "City name",
+				Validators:          textValidators(),
 			},
 			"touristic": schema.BoolAttribute{
 				Required:            true,
 				MarkdownDescription: "Whether the city is touristic or not",
 			},
+			"force_destroy": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Delete the houses and stores of the city before deleting it",
+			},
+			"deletion_protection": deletionProtectionAttribute(),
+			"adopt_existing":      adoptExistingAttribute(),
+			"house":               cityHouseAttribute(),
+			"store":               cityStoreAttribute(),
 		},
 	}
 }
 
+func (r *CityResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *CityResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateCityChildren(ctx, data)...)
+}
+
+func (r *CityResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: idsToInt64StateUpgrader([]string{"id"}, []string{"house", "store"}),
+	}
+}
+
 func (r *CityResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SendoraCityClient)
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.SendoraCityClient, got: %T."+
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
 				"Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Client
+	r.maxInhabitants = providerData.MaxInhabitants
+	r.storeTypes = providerData.StoreTypes
+	r.enforceUniqueCityNames = providerData.EnforceUniqueCityNames
+	r.nameAffixes = providerData.NameAffixes
 	r.url = "cities"
 }
 
@@ -93,10 +151,51 @@ func (r *CityResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	touristic := data.Touristic.ValueBool()
-	body := &City{
-		Name:      data.Name.ValueString(),
-		Touristic: &touristic,
+	body := cityBody(data)
+	body.Name = r.nameAffixes.apiName(body.Name)
+
+	if r.enforceUniqueCityNames {
+		// The plan was checked already, the check is repeated under lock
+		// against the cities created since, by this apply in particular.
+		defer lockCityName(body.Name)()
+		resp.Diagnostics.Append(checkUniqueCityName(r.client, path.Root("name"), body.Name, 0, data.AdoptExisting.ValueBool())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if data.AdoptExisting.ValueBool() {
+		existing, err := adoptableCity(r.client, body.Name)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to look up an existing city to adopt, got error: %s", err))
+			return
+		}
+		if existing != nil {
+			// Children of the adopted city with a planned natural key are
+			// adopted along with it.
+			prior, diags := r.adoptedCityChildren(ctx, existing.Id, data)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			id := strconv.Itoa(existing.Id)
+			if err = updateObject(r.client, fmt.Sprintf("%s/%s", r.url, id), body); err != nil {
+				resp.Diagnostics.AddError("Client Error",
+					fmt.Sprintf("Unable to update adopted city with id %s, got error: %s", id, err))
+				return
+			}
+
+			data.Id = types.Int64Value(int64(existing.Id))
+			data.CreatedAt = createdAtValue(existing.Timestamp)
+
+			resp.Diagnostics.Append(r.applyCityChildren(ctx, existing.Id, data, prior)...)
+
+			tflog.Trace(ctx, "adopted an existing city resource", map[string]any{"id": id})
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
 	}
 
 	jsonBody, err := json.Marshal(body)
@@ -128,7 +227,10 @@ func (r *CityResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	data.Id = types.StringValue(strconv.Itoa(city.Id))
+	data.Id = types.Int64Value(int64(city.Id))
+	data.CreatedAt = createdAtValue(city.Timestamp)
+
+	resp.Diagnostics.Append(r.applyCityChildren(ctx, city.Id, data, nil)...)
 
 	tflog.Trace(ctx, "created a city resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -143,7 +245,7 @@ func (r *CityResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	res, err := r.client.DoRead(fmt.Sprintf("%s/%s", r.url, data.Id.ValueString()))
+	res, err := r.client.DoRead(fmt.Sprintf("%s/%d", r.url, data.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to read city, got error: %s", err))
@@ -170,25 +272,50 @@ func (r *CityResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	data.Name = types.StringValue(city.Name)
-	data.Touristic = types.BoolValue(*city.Touristic)
+	refreshCityModel(data, city)
+	data.Name = r.nameAffixes.configNameValue(data.Name)
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
+	}
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
+
+	if !data.House.IsNull() || !data.Store.IsNull() {
+		houses, stores, err := listCityChildren(r.client, city.Id)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to list city %d children, got error: %s", city.Id, err))
+			return
+		}
+		resp.Diagnostics.Append(refreshCityChildren(ctx, data, houses, stores, r.nameAffixes)...)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *CityResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data *CityResourceModel
+	var data, state *CityResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	touristic := data.Touristic.ValueBool()
-	body := &City{
-		Name:      data.Name.ValueString(),
-		Touristic: &touristic,
+	body := cityBody(data)
+	body.Name = r.nameAffixes.apiName(body.Name)
+
+	if r.enforceUniqueCityNames && !data.Name.Equal(state.Name) {
+		defer lockCityName(body.Name)()
+		resp.Diagnostics.Append(checkUniqueCityName(r.client, path.Root("name"), body.Name, int(data.Id.ValueInt64()), false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
 	jsonBody, err := json.Marshal(body)
@@ -198,13 +325,15 @@ func (r *CityResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	id := data.Id.ValueString()
+	id := strconv.FormatInt(data.Id.ValueInt64(), 10)
 	if _, err = r.client.DoUpdate(fmt.Sprintf("%s/%s", r.url, id), jsonBody); err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to update city with id %s, got error: %s", id, err))
 		return
 	}
 
+	resp.Diagnostics.Append(r.applyCityChildren(ctx, int(data.Id.ValueInt64()), data, state)...)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -217,7 +346,22 @@ func (r *CityResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	id := data.Id.ValueString()
+	id := strconv.FormatInt(data.Id.ValueInt64(), 10)
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.Append(deletionProtectionError("city", id))
+		return
+	}
+
+	if data.ForceDestroy.ValueBool() {
+		resp.Diagnostics.Append(r.deleteChildren(ctx, int(data.Id.ValueInt64()))...)
+	} else if !data.House.IsNull() || !data.Store.IsNull() {
+		remaining := &CityResourceModel{House: types.SetNull(cityHouseObjectType), Store: types.SetNull(cityStoreObjectType)}
+		resp.Diagnostics.Append(r.applyCityChildren(ctx, int(data.Id.ValueInt64()), remaining, data)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if _, err := r.client.DoDelete(fmt.Sprintf("%s/%s", r.url, id)); err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to delete city with id %s, got error: %s", id, err))
@@ -225,6 +369,134 @@ func (r *CityResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 }
 
+// deleteChildren deletes every house and store of the city, and reports the
+// removed objects in a warning.
+func (r *CityResource) deleteChildren(ctx context.Context, cityId int) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	id := strconv.Itoa(cityId)
+	houses, stores, err := listCityChildren(r.client, cityId)
+	if err != nil {
+		diags.AddError("Client Error",
+			fmt.Sprintf("Unable to list city %s children, got error: %s", id, err))
+		return diags
+	}
+
+	urls := []string{}
+	for _, house := range houses {
+		urls = append(urls, fmt.Sprintf("houses/%d", house.Id))
+	}
+	for _, store := range stores {
+		urls = append(urls, fmt.Sprintf("stores/%d", store.Id))
+	}
+
+	errs := runConcurrently(len(urls), maxConcurrentRequests, func(i int) error {
+		return retry(ctx, func() error {
+			return deleteObject(r.client, urls[i])
+		})
+	})
+
+	removed := []string{}
+	for i, err := range errs {
+		if err != nil {
+			diags.AddError("Client Error",
+				fmt.Sprintf("Unable to delete %s of city %s, got error: %s", urls[i], id, err))
+			continue
+		}
+		removed = append(removed, urls[i])
+	}
+
+	if len(removed) > 0 {
+		tflog.Debug(ctx, "deleted city children", map[string]any{"city_id": id, "children": removed})
+		diags.AddWarning("City Children Deleted",
+			fmt.Sprintf("force_destroy removed %d object(s) from city %s before deleting it: %s",
+				len(removed), id, strings.Join(removed, ", ")))
+	}
+	return diags
+}
+
+func (r *CityResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// No attribute requires the replacement of a city, so the plan only
+	// removes one when destroying it.
+	modifyPlanDeletionProtection(ctx, "city", false, req, resp)
+	if r.enforceUniqueCityNames {
+		modifyPlanUniqueCityName(ctx, r.client, r.nameAffixes, req, resp)
+	}
+
+	if !req.Plan.Raw.IsNull() {
+		var plan *CityResourceModel
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+		houses, stores, diags := cityChildrenModels(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		for _, house := range houses {
+			resp.Diagnostics.Append(validateMaxInhabitants(r.maxInhabitants, path.Root("house"), house.Inhabitants)...)
+		}
+		for _, store := range stores {
+			resp.Diagnostics.Append(validateStoreType(r.storeTypes.list(ctx), path.Root("store"), store.Type)...)
+		}
+	}
+
+	if req.State.Raw.IsNull() || !req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var data *CityResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := strconv.FormatInt(data.Id.ValueInt64(), 10)
+	houses, stores, err := listCityChildren(r.client, int(data.Id.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to list city %s children, got error: %s", id, err))
+		return
+	}
+
+	// Nested houses and stores are deleted along with the city.
+	managed, diags := cityChildrenIds(ctx, data)
+	resp.Diagnostics.Append(diags...)
+
+	houseIds := []string{}
+	for _, house := range houses {
+		if !managed[fmt.Sprintf("houses/%d", house.Id)] {
+			houseIds = append(houseIds, strconv.Itoa(house.Id))
+		}
+	}
+	storeIds := []string{}
+	for _, store := range stores {
+		if !managed[fmt.Sprintf("stores/%d", store.Id)] {
+			storeIds = append(storeIds, strconv.Itoa(store.Id))
+		}
+	}
+	if len(houseIds) == 0 && len(storeIds) == 0 {
+		return
+	}
+	children := fmt.Sprintf("%d house(s) [%s] and %d store(s) [%s]",
+		len(houseIds), strings.Join(houseIds, ", "), len(storeIds), strings.Join(storeIds, ", "))
+
+	// Children managed in the same configuration are destroyed before the city,
+	// so this cannot be an error without blocking legitimate destroys.
+	if data.ForceDestroy.ValueBool() {
+		resp.Diagnostics.AddWarning("City Children Will Be Deleted",
+			fmt.Sprintf("City %s still has %s, force_destroy will delete them along with the city.", id, children))
+		return
+	}
+	resp.Diagnostics.AddWarning("City Still Has Children",
+		fmt.Sprintf("City %s still has %s. Unless they are destroyed by this plan too, deleting the city will fail. "+
+			"Remove them first or set force_destroy = true.", id, children))
+}
+
 func (r *CityResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id, err := resolveImportId(r.client, r.nameAffixes, "city", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import Identifier",
+			fmt.Sprintf("Unable to import city, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), int64(id))...)
 }
@@ -0,0 +1,555 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type CityResourceHouseModel struct {
+	Id          types.Int64  `tfsdk:"id"`
+	Address     types.String `tfsdk:"address"`
+	Inhabitants types.Int64  `tfsdk:"inhabitants"`
+}
+
+type CityResourceStoreModel struct {
+	Id      types.Int64    `tfsdk:"id"`
+	Name    types.String   `tfsdk:"name"`
+	Address types.String   `tfsdk:"address"`
+	Type    StoreTypeValue `tfsdk:"type"`
+}
+
+var cityHouseObjectType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"id":          types.Int64Type,
+	"address":     types.StringType,
+	"inhabitants": types.Int64Type,
+}}
+
+var cityStoreObjectType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"id":      types.Int64Type,
+	"name":    types.StringType,
+	"address": types.StringType,
+	"type":    StoreTypeType{},
+}}
+
+// cityHouseAttribute returns the nested houses attribute of the city resource.
+func cityHouseAttribute() schema.Attribute {
+	return schema.SetNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: "Houses created along with the city, identified by address",
+		PlanModifiers: []planmodifier.Set{
+			cityChildIdPlanModifier{key: "address"},
+		},
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"id": schema.Int64Attribute{
+					Computed:            true,
+					MarkdownDescription: "House identifier",
+				},
+				"address": schema.StringAttribute{
+					Required:            true,
+					MarkdownDescription: "House address",
+					Validators:          textValidators(),
+				},
+				"inhabitants": schema.Int64Attribute{
+					Required:            true,
+					MarkdownDescription: "House inhabitants count",
+					Validators:          inhabitantsValidators(),
+				},
+			},
+		},
+	}
+}
+
+// cityStoreAttribute returns the nested stores attribute of the city resource.
+func cityStoreAttribute() schema.Attribute {
+	return schema.SetNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: "Stores created along with the city, identified by name",
+		PlanModifiers: []planmodifier.Set{
+			cityChildIdPlanModifier{key: "name"},
+		},
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"id": schema.Int64Attribute{
+					Computed:            true,
+					MarkdownDescription: "Store identifier",
+				},
+				"name": schema.StringAttribute{
+					Required:            true,
+					MarkdownDescription: "Store name",
+					Validators:          textValidators(),
+				},
+				"address": schema.StringAttribute{
+					Required:            true,
+					MarkdownDescription: "Store address",
+					Validators:          textValidators(),
+				},
+				"type": schema.StringAttribute{
+					CustomType:          StoreTypeType{},
+					Required:            true,
+					MarkdownDescription: "Store type, in any case",
+					Validators:          textValidators(),
+				},
+			},
+		},
+	}
+}
+
+var _ planmodifier.Set = cityChildIdPlanModifier{}
+
+// cityChildIdPlanModifier plans the identifier of the nested houses or stores
+// found in state with the same natural key, the key attribute, as they are
+// updated in place. Otherwise any change to the set would show the identifiers
+// of every element as unknown, as set elements cannot be matched with their
+// state by the identifier plan modifiers.
+type cityChildIdPlanModifier struct {
+	key string
+}
+
+func (m cityChildIdPlanModifier) Description(ctx context.Context) string {
+	return fmt.Sprintf("Keeps the identifiers of the elements whose %s does not change.", m.key)
+}
+
+func (m cityChildIdPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Keeps the identifiers of the elements whose `%s` does not change.", m.key)
+}
+
+func (m cityChildIdPlanModifier) PlanModifySet(ctx context.Context, req planmodifier.SetRequest, resp *planmodifier.SetResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+
+	ids := make(map[string]attr.Value)
+	for _, element := range req.StateValue.Elements() {
+		object, ok := element.(types.Object)
+		if !ok {
+			continue
+		}
+		key, ok := object.Attributes()[m.key].(types.String)
+		if ok && !key.IsNull() && !key.IsUnknown() {
+			ids[key.ValueString()] = object.Attributes()["id"]
+		}
+	}
+
+	elements := make([]attr.Value, 0, len(req.PlanValue.Elements()))
+	for _, element := range req.PlanValue.Elements() {
+		object, ok := element.(types.Object)
+		if !ok || object.IsNull() || object.IsUnknown() {
+			elements = append(elements, element)
+			continue
+		}
+		attributes := object.Attributes()
+		key, ok := attributes[m.key].(types.String)
+		id, found := ids[key.ValueString()]
+		if !ok || key.IsUnknown() || !found || !attributes["id"].IsUnknown() {
+			elements = append(elements, element)
+			continue
+		}
+		attributes["id"] = id
+		planned, diags := types.ObjectValue(object.AttributeTypes(ctx), attributes)
+		resp.Diagnostics.Append(diags...)
+		elements = append(elements, planned)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, diags := types.SetValue(req.PlanValue.ElementType(ctx), elements)
+	resp.Diagnostics.Append(diags...)
+	resp.PlanValue = planned
+}
+
+// cityChild is a nested house or store of a city resource.
+type cityChild struct {
+	id   int
+	body any
+}
+
+// validateCityChildren checks that nested houses and stores have unique natural keys.
+func validateCityChildren(ctx context.Context, data *CityResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	houses, stores, d := cityChildrenModels(ctx, data)
+	diags.Append(d...)
+
+	addresses := make(map[string]bool)
+	for _, house := range houses {
+		if house.Address.IsUnknown() {
+			continue
+		}
+		if addresses[house.Address.ValueString()] {
+			diags.AddAttributeError(path.Root("house"), "Duplicate House",
+				fmt.Sprintf("Address %q is used by several houses", house.Address.ValueString()))
+		}
+		addresses[house.Address.ValueString()] = true
+	}
+
+	names := make(map[string]bool)
+	for _, store := range stores {
+		if store.Name.IsUnknown() {
+			continue
+		}
+		if names[store.Name.ValueString()] {
+			diags.AddAttributeError(path.Root("store"), "Duplicate Store",
+				fmt.Sprintf("Name %q is used by several stores", store.Name.ValueString()))
+		}
+		names[store.Name.ValueString()] = true
+	}
+	return diags
+}
+
+// applyCityChildren creates, updates and deletes the nested houses and stores
+// so that they match the plan, diffing them by natural key against the prior
+// state. The plan is updated with the identifiers of the children, and keeps
+// the prior values of failed operations so that partial progress is saved.
+func (r *CityResource) applyCityChildren(ctx context.Context, cityId int, data, prior *CityResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	houses, stores, d := cityChildrenModels(ctx, data)
+	diags.Append(d...)
+	priorHouses, priorStores, d := cityChildrenModels(ctx, prior)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	plannedHouses := make(map[string]cityChild)
+	for _, house := range houses {
+		plannedHouses[house.Address.ValueString()] = cityChild{body: &House{
+			CityId:      cityId,
+			Address:     house.Address.ValueString(),
+			Inhabitants: intPointer(house.Inhabitants),
+		}}
+	}
+	priorHousesByKey := make(map[string]CityResourceHouseModel)
+	existingHouses := make(map[string]cityChild)
+	for _, house := range priorHouses {
+		priorHousesByKey[house.Address.ValueString()] = house
+		existingHouses[house.Address.ValueString()] = cityChild{id: int(house.Id.ValueInt64()), body: &House{
+			CityId:      cityId,
+			Address:     house.Address.ValueString(),
+			Inhabitants: intPointer(house.Inhabitants),
+		}}
+	}
+
+	storeTypes := r.storeTypes.list(ctx)
+	plannedStores := make(map[string]cityChild)
+	for _, store := range stores {
+		plannedStores[store.Name.ValueString()] = cityChild{body: &Store{
+			CityId:  cityId,
+			Name:    r.nameAffixes.apiName(store.Name.ValueString()),
+			Address: store.Address.ValueString(),
+			Type:    storeTypeBody(store.Type.ValueString(), storeTypes),
+		}}
+	}
+	priorStoresByKey := make(map[string]CityResourceStoreModel)
+	existingStores := make(map[string]cityChild)
+	for _, store := range priorStores {
+		priorStoresByKey[store.Name.ValueString()] = store
+		existingStores[store.Name.ValueString()] = cityChild{id: int(store.Id.ValueInt64()), body: &Store{
+			CityId:  cityId,
+			Name:    r.nameAffixes.apiName(store.Name.ValueString()),
+			Address: store.Address.ValueString(),
+			Type:    storeTypeBody(store.Type.ValueString(), storeTypes),
+		}}
+	}
+
+	houseIds, d := r.syncCityChildren(ctx, "houses", plannedHouses, existingHouses)
+	diags.Append(d...)
+	storeIds, d := r.syncCityChildren(ctx, "stores", plannedStores, existingStores)
+	diags.Append(d...)
+
+	if !data.House.IsNull() || len(houseIds) > 0 {
+		result := []CityResourceHouseModel{}
+		for _, house := range houses {
+			id, ok := houseIds[house.Address.ValueString()]
+			if !ok {
+				continue
+			}
+			delete(houseIds, house.Address.ValueString())
+			house.Id = types.Int64Value(int64(id))
+			result = append(result, house)
+		}
+		for key := range houseIds {
+			result = append(result, priorHousesByKey[key])
+		}
+		data.House, d = types.SetValueFrom(ctx, cityHouseObjectType, result)
+		diags.Append(d...)
+	}
+
+	if !data.Store.IsNull() || len(storeIds) > 0 {
+		result := []CityResourceStoreModel{}
+		for _, store := range stores {
+			id, ok := storeIds[store.Name.ValueString()]
+			if !ok {
+				continue
+			}
+			delete(storeIds, store.Name.ValueString())
+			store.Id = types.Int64Value(int64(id))
+			result = append(result, store)
+		}
+		for key := range storeIds {
+			result = append(result, priorStoresByKey[key])
+		}
+		data.Store, d = types.SetValueFrom(ctx, cityStoreObjectType, result)
+		diags.Append(d...)
+	}
+
+	return diags
+}
+
+// adoptedCityChildren returns a model holding the existing houses and stores of
+// an adopted city whose natural key is planned, to be passed to
+// applyCityChildren as prior state so that they are updated rather than
+// created again. The other children of the city are left alone.
+func (r *CityResource) adoptedCityChildren(ctx context.Context, cityId int, data *CityResourceModel) (*CityResourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	prior := &CityResourceModel{
+		House: types.SetNull(cityHouseObjectType),
+		Store: types.SetNull(cityStoreObjectType),
+	}
+	plannedHouses, plannedStores, d := cityChildrenModels(ctx, data)
+	diags.Append(d...)
+	if diags.HasError() || (len(plannedHouses) == 0 && len(plannedStores) == 0) {
+		return prior, diags
+	}
+
+	houses, stores, err := listCityChildren(r.client, cityId)
+	if err != nil {
+		diags.AddError("Client Error",
+			fmt.Sprintf("Unable to list city %d children to adopt, got error: %s", cityId, err))
+		return prior, diags
+	}
+
+	existingHouses := []CityResourceHouseModel{}
+	for _, planned := range plannedHouses {
+		matches := []House{}
+		ids := []int{}
+		for _, house := range houses {
+			if house.Address == planned.Address.ValueString() {
+				matches = append(matches, house)
+				ids = append(ids, house.Id)
+			}
+		}
+		if err = checkAdoptable("houses", fmt.Sprintf("at %q in city %d", planned.Address.ValueString(), cityId), ids); err != nil {
+			diags.AddAttributeError(path.Root("house"), "Client Error", err.Error())
+			continue
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		existingHouses = append(existingHouses, CityResourceHouseModel{
+			Id:          idValue(matches[0].Id),
+			Address:     planned.Address,
+			Inhabitants: int64Value(matches[0].Inhabitants),
+		})
+	}
+
+	existingStores := []CityResourceStoreModel{}
+	for _, planned := range plannedStores {
+		name := r.nameAffixes.apiName(planned.Name.ValueString())
+		matches := []Store{}
+		ids := []int{}
+		for _, store := range stores {
+			if store.Name == name {
+				matches = append(matches, store)
+				ids = append(ids, store.Id)
+			}
+		}
+		if err = checkAdoptable("stores", fmt.Sprintf("named %q in city %d", name, cityId), ids); err != nil {
+			diags.AddAttributeError(path.Root("store"), "Client Error", err.Error())
+			continue
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		existingStores = append(existingStores, CityResourceStoreModel{
+			Id:      idValue(matches[0].Id),
+			Name:    planned.Name,
+			Address: types.StringValue(matches[0].Address),
+			Type:    storeTypeValue(matches[0].Type),
+		})
+	}
+
+	prior.House, d = types.SetValueFrom(ctx, cityHouseObjectType, existingHouses)
+	diags.Append(d...)
+	prior.Store, d = types.SetValueFrom(ctx, cityStoreObjectType, existingStores)
+	diags.Append(d...)
+	return prior, diags
+}
+
+// syncCityChildren creates the planned children that do not exist yet, updates
+// the ones that changed and deletes the existing ones that are no longer
+// planned. It returns the identifiers of the children that exist afterwards,
+// keyed by natural key.
+func (r *CityResource) syncCityChildren(ctx context.Context, kind string, planned, existing map[string]cityChild) (map[string]int, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	type operation struct {
+		key    string
+		method string
+		child  cityChild
+	}
+	operations := []operation{}
+	ids := make(map[string]int)
+
+	for _, key := range sortedKeys(planned) {
+		child := planned[key]
+		current, ok := existing[key]
+		switch {
+		case !ok:
+			operations = append(operations, operation{key: key, method: "create", child: child})
+		case !reflect.DeepEqual(current.body, child.body):
+			child.id = current.id
+			ids[key] = current.id
+			operations = append(operations, operation{key: key, method: "update", child: child})
+		default:
+			ids[key] = current.id
+		}
+	}
+	for _, key := range sortedKeys(existing) {
+		if _, ok := planned[key]; !ok {
+			ids[key] = existing[key].id
+			operations = append(operations, operation{key: key, method: "delete", child: existing[key]})
+		}
+	}
+
+	createdIds := make([]int, len(operations))
+	errs := runConcurrently(len(operations), maxConcurrentRequests, func(i int) error {
+		operation := operations[i]
+		url := fmt.Sprintf("%s/%d", kind, operation.child.id)
+		switch operation.method {
+		case "create":
+			created := &struct {
+				Id int `json:"id"`
+			}{}
+			if err := createObject(r.client, kind, operation.child.body, created); err != nil {
+				return err
+			}
+			createdIds[i] = created.Id
+			return nil
+		case "update":
+			return retry(ctx, func() error {
+				return updateObject(r.client, url, operation.child.body)
+			})
+		default:
+			return retry(ctx, func() error {
+				return deleteObject(r.client, url)
+			})
+		}
+	})
+
+	for i, operation := range operations {
+		if errs[i] != nil {
+			diags.AddError("Client Error",
+				fmt.Sprintf("Unable to %s %s %q, got error: %s", operation.method, kind, operation.key, errs[i]))
+			continue
+		}
+		switch operation.method {
+		case "create":
+			ids[operation.key] = createdIds[i]
+		case "delete":
+			delete(ids, operation.key)
+		}
+	}
+	return ids, diags
+}
+
+// refreshCityChildren updates the nested houses and stores with their values
+// from the API, store names without the provider name affixes. Children
+// deleted outside of Terraform are removed.
+func refreshCityChildren(ctx context.Context, data *CityResourceModel, houses []House, stores []Store, names nameAffixes) diag.Diagnostics {
+	var diags, d diag.Diagnostics
+
+	stateHouses, stateStores, d := cityChildrenModels(ctx, data)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if !data.House.IsNull() {
+		housesById := make(map[int64]House)
+		for _, house := range houses {
+			housesById[int64(house.Id)] = house
+		}
+		result := []CityResourceHouseModel{}
+		for _, house := range stateHouses {
+			current, ok := housesById[house.Id.ValueInt64()]
+			if !ok {
+				continue
+			}
+			result = append(result, CityResourceHouseModel{
+				Id:          house.Id,
+				Address:     types.StringValue(current.Address),
+				Inhabitants: int64Value(current.Inhabitants),
+			})
+		}
+		data.House, d = types.SetValueFrom(ctx, cityHouseObjectType, result)
+		diags.Append(d...)
+	}
+
+	if !data.Store.IsNull() {
+		storesById := make(map[int64]Store)
+		for _, store := range stores {
+			storesById[int64(store.Id)] = store
+		}
+		result := []CityResourceStoreModel{}
+		for _, store := range stateStores {
+			current, ok := storesById[store.Id.ValueInt64()]
+			if !ok {
+				continue
+			}
+			result = append(result, CityResourceStoreModel{
+				Id:      store.Id,
+				Name:    types.StringValue(names.configName(current.Name)),
+				Address: types.StringValue(current.Address),
+				Type:    refreshedStoreType(store.Type, current.Type),
+			})
+		}
+		data.Store, d = types.SetValueFrom(ctx, cityStoreObjectType, result)
+		diags.Append(d...)
+	}
+
+	return diags
+}
+
+// cityChildrenIds returns the identifiers of the nested houses and stores.
+func cityChildrenIds(ctx context.Context, data *CityResourceModel) (map[string]bool, diag.Diagnostics) {
+	houses, stores, diags := cityChildrenModels(ctx, data)
+
+	ids := make(map[string]bool)
+	for _, house := range houses {
+		ids[fmt.Sprintf("houses/%d", house.Id.ValueInt64())] = true
+	}
+	for _, store := range stores {
+		ids[fmt.Sprintf("stores/%d", store.Id.ValueInt64())] = true
+	}
+	return ids, diags
+}
+
+// cityChildrenModels returns the nested houses and stores of a city model.
+func cityChildrenModels(ctx context.Context, data *CityResourceModel) ([]CityResourceHouseModel, []CityResourceStoreModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	houses := []CityResourceHouseModel{}
+	stores := []CityResourceStoreModel{}
+	if data == nil {
+		return houses, stores, diags
+	}
+	if !data.House.IsNull() && !data.House.IsUnknown() {
+		diags.Append(data.House.ElementsAs(ctx, &houses, false)...)
+	}
+	if !data.Store.IsNull() && !data.Store.IsUnknown() {
+		diags.Append(data.Store.ElementsAs(ctx, &stores, false)...)
+	}
+	return houses, stores, diags
+}
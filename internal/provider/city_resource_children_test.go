@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCityChildIdPlanModifier(t *testing.T) {
+	house := func(id types.Int64, address string, inhabitants int64) attr.Value {
+		return types.ObjectValueMust(cityHouseObjectType.AttrTypes, map[string]attr.Value{
+			"id":          id,
+			"address":     types.StringValue(address),
+			"inhabitants": types.Int64Value(inhabitants),
+		})
+	}
+	set := func(elements ...attr.Value) types.Set {
+		return types.SetValueMust(cityHouseObjectType, elements)
+	}
+	state := set(house(types.Int64Value(1), "first", 1), house(types.Int64Value(2), "second", 2))
+
+	tests := []struct {
+		name  string
+		state types.Set
+		plan  types.Set
+		want  types.Set
+	}{
+		{
+			name:  "edited element",
+			state: state,
+			plan:  set(house(types.Int64Unknown(), "first", 10), house(types.Int64Unknown(), "second", 2)),
+			want:  set(house(types.Int64Value(1), "first", 10), house(types.Int64Value(2), "second", 2)),
+		},
+		{
+			name:  "added element",
+			state: state,
+			plan:  set(house(types.Int64Unknown(), "first", 1), house(types.Int64Unknown(), "third", 3)),
+			want:  set(house(types.Int64Value(1), "first", 1), house(types.Int64Unknown(), "third", 3)),
+		},
+		{
+			name:  "creation",
+			state: types.SetNull(cityHouseObjectType),
+			plan:  set(house(types.Int64Unknown(), "first", 1)),
+			want:  set(house(types.Int64Unknown(), "first", 1)),
+		},
+		{
+			name:  "removed set",
+			state: state,
+			plan:  types.SetNull(cityHouseObjectType),
+			want:  types.SetNull(cityHouseObjectType),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := planmodifier.SetRequest{StateValue: test.state, PlanValue: test.plan}
+			resp := &planmodifier.SetResponse{PlanValue: test.plan}
+			cityChildIdPlanModifier{key: "address"}.PlanModifySet(context.Background(), req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+			}
+			if !resp.PlanValue.Equal(test.want) {
+				t.Errorf("got %s, want %s", resp.PlanValue, test.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package provider
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
 )
 
 func TestAccCityResource(t *testing.T) {
@@ -17,6 +24,7 @@ func TestAccCityResource(t *testing.T) {
 				Config: testAccCityResourceConfig("city-test-name-init"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("sendoracity_city.test", "id"),
+					resource.TestCheckResourceAttrSet("sendoracity_city.test", "created_at"),
 					resource.TestCheckResourceAttr("sendoracity_city.test", "name", "city-test-name-init"),
 					resource.TestCheckResourceAttr("sendoracity_city.test", "touristic", "false"),
 				),
@@ -27,6 +35,13 @@ func TestAccCityResource(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			// ImportState by natural key testing
+			{
+				ResourceName:      "sendoracity_city.test",
+				ImportState:       true,
+				ImportStateId:     "city:city-test-name-init",
+				ImportStateVerify: true,
+			},
 			// Update and Read testing
 			{
 				Config: testAccCityResourceConfig("city-test-name-updated"),
@@ -47,3 +62,315 @@ resource "sendoracity_city" "test" {
 }
 `, name)
 }
+
+func TestAccCityResourceForceDestroy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create a city, then a house outside of Terraform
+			{
+				Config: testAccCityResourceForceDestroyConfig("city-test-force-destroy"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_city.test", "force_destroy", "true"),
+					testAccCreateUnmanagedHouse("sendoracity_city.test", "city-test-force-destroy-house"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase and must remove the unmanaged house
+		},
+	})
+}
+
+func testAccCityResourceForceDestroyConfig(name string) string {
+	return fmt.Sprintf(`
+resource "sendoracity_city" "test" {
+  name          = "%s"
+  touristic     = false
+  force_destroy = true
+}
+`, name)
+}
+
+// testAccCreateUnmanagedHouse creates a house in the given city directly through the API.
+func testAccCreateUnmanagedHouse(cityResourceName, address string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		city, ok := s.RootModule().Resources[cityResourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", cityResourceName)
+		}
+
+		cityId, err := strconv.Atoi(city.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		body, err := json.Marshal(&House{CityId: cityId, Address: address, Inhabitants: pointer(1)})
+		if err != nil {
+			return err
+		}
+
+		res, err := client.NewClient(os.Getenv("BASE_URI")).DoCreate("houses", body)
+		if err != nil {
+			return err
+		}
+		return res.Body.Close()
+	}
+}
+
+func TestAccCityResourceDeletionProtection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create a protected city
+			{
+				Config: testAccCityResourceDeletionProtectionConfig("city-test-deletion-protection", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_city.test", "deletion_protection", "true"),
+				),
+			},
+			// Destroying the protected city fails
+			{
+				Config:      testAccCityResourceDeletionProtectionConfig("city-test-deletion-protection", true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Deletion Protection Enabled"),
+			},
+			// Disable the protection so the city can be deleted
+			{
+				Config: testAccCityResourceDeletionProtectionConfig("city-test-deletion-protection", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_city.test", "deletion_protection", "false"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccCityResourceDeletionProtectionConfig(name string, protected bool) string {
+	return fmt.Sprintf(`
+resource "sendoracity_city" "test" {
+  name                = "%s"
+  touristic           = false
+  deletion_protection = %t
+}
+`, name, protected)
+}
+
+func TestAccCityResourceChildren(t *testing.T) {
+	var houseId, storeId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create the city along with its houses and stores
+			{
+				Config: testAccCityResourceChildrenConfig(10, "Food"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_city.test", "house.#", "2"),
+					resource.TestCheckResourceAttr("sendoracity_city.test", "store.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("sendoracity_city.test", "house.*", map[string]string{
+						"address":     "city-test-children-house-1",
+						"inhabitants": "10",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("sendoracity_city.test", "store.*", map[string]string{
+						"name": "city-test-children-store",
+						"type": "Food",
+					}),
+					testAccCheckCityChildId("house", "address", "city-test-children-house-2", &houseId),
+					testAccCheckCityChildId("store", "name", "city-test-children-store", &storeId),
+				),
+			},
+			// Update the children in place, the untouched house keeps its identifier
+			{
+				Config: testAccCityResourceChildrenConfig(20, "Sports"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCityChildId("house", "address", "city-test-children-house-2", &houseId),
+					testAccCheckCityChildId("store", "name", "city-test-children-store", &storeId),
+					resource.TestCheckTypeSetElemNestedAttrs("sendoracity_city.test", "house.*", map[string]string{
+						"address":     "city-test-children-house-1",
+						"inhabitants": "20",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("sendoracity_city.test", "store.*", map[string]string{
+						"name": "city-test-children-store",
+						"type": "Sports",
+					}),
+				),
+			},
+			// Delete testing automatically occurs in TestCase and must remove the children first
+		},
+	})
+}
+
+// testAccCheckCityChildId checks the identifier of the nested house or store
+// of sendoracity_city.test whose key attribute equals value. The identifier is
+// saved into id when empty, and compared with it otherwise.
+func testAccCheckCityChildId(attribute, key, value string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		res, ok := s.RootModule().Resources["sendoracity_city.test"]
+		if !ok {
+			return fmt.Errorf("resource sendoracity_city.test not found in state")
+		}
+		for name, current := range res.Primary.Attributes {
+			prefix := strings.TrimSuffix(name, "."+key)
+			if prefix == name || !strings.HasPrefix(name, attribute+".") || current != value {
+				continue
+			}
+			got := res.Primary.Attributes[prefix+".id"]
+			if *id == "" {
+				*id = got
+			} else if got != *id {
+				return fmt.Errorf("%s %q has id %s, want %s", attribute, value, got, *id)
+			}
+			return nil
+		}
+		return fmt.Errorf("%s %q not found in state", attribute, value)
+	}
+}
+
+func testAccCityResourceChildrenConfig(inhabitants int, storeType string) string {
+	return fmt.Sprintf(`
+resource "sendoracity_city" "test" {
+  name      = "city-test-children"
+  touristic = false
+
+  house = [
+    {
+      address     = "city-test-children-house-1"
+      inhabitants = %[1]d
+    },
+    {
+      address     = "city-test-children-house-2"
+      inhabitants = 1
+    },
+  ]
+
+  store = [
+    {
+      name    = "city-test-children-store"
+      address = "city-test-children-store-address"
+      type    = "%[2]s"
+    },
+  ]
+}
+`, inhabitants, storeType)
+}
+
+func TestAccCityResourceUniqueName(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create the first city
+			{
+				Config: testAccCityResourceUniqueNameConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sendoracity_city.first", "id"),
+				),
+			},
+			// A second city with the same name in another case is rejected
+			{
+				Config: testAccCityResourceUniqueNameConfig(`
+resource "sendoracity_city" "second" {
+  name      = "CITY-TEST-UNIQUE-NAME"
+  touristic = false
+}
+`),
+				ExpectError: regexp.MustCompile(`already used by city [0-9]+ \("city-test-unique-name"\)`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccCityResourceUniqueNameConfig(extra string) string {
+	return fmt.Sprintf(`
+provider "sendoracity" {
+  enforce_unique_city_names = true
+}
+
+resource "sendoracity_city" "first" {
+  name      = "city-test-unique-name"
+  touristic = false
+}
+%s`, extra)
+}
+
+func TestAccCityResourceAdoptExistingChildren(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Adopt a city created outside of Terraform along with its house,
+			// which is updated rather than created again
+			{
+				PreConfig: func() {
+					testAccCreateUnmanagedCityWithHouse(t, "city-test-adopt-children", "city-test-adopt-children-house")
+				},
+				Config: `
+resource "sendoracity_city" "test" {
+  name           = "city-test-adopt-children"
+  touristic      = false
+  adopt_existing = true
+
+  house = [
+    {
+      address     = "city-test-adopt-children-house"
+      inhabitants = 5
+    },
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_city.test", "house.#", "1"),
+					func(s *terraform.State) error {
+						cityId, err := strconv.Atoi(s.RootModule().Resources["sendoracity_city.test"].Primary.ID)
+						if err != nil {
+							return err
+						}
+						houses, err := listCityHouses(client.NewClient(os.Getenv("BASE_URI")), cityId)
+						if err != nil {
+							return err
+						}
+						if len(houses) != 1 || *houses[0].Inhabitants != 5 {
+							return fmt.Errorf("got houses %+v, want a single house with 5 inhabitants", houses)
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// testAccCreateUnmanagedCityWithHouse creates a city with a house outside of
+// Terraform.
+func testAccCreateUnmanagedCityWithHouse(t *testing.T, name, address string) {
+	c := client.NewClient(os.Getenv("BASE_URI"))
+
+	body, err := json.Marshal(&City{Name: name, Touristic: pointer(false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.DoCreate("cities", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	city := &City{}
+	if err = decodeResponse(res, city); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err = json.Marshal(&House{CityId: city.Id, Address: address, Inhabitants: pointer(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = c.DoCreate("houses", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = res.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
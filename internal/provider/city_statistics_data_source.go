@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+var _ datasource.DataSource = &CityStatisticsDataSource{}
+
+func NewCityStatisticsDataSource() datasource.DataSource {
+	return &CityStatisticsDataSource{}
+}
+
+type CityStatisticsDataSource struct {
+	client      *client.SendoraCityClient
+	nameAffixes nameAffixes
+}
+
+type CityStatisticsDataSourceModel struct {
+	CityId    types.Int64                      `tfsdk:"city_id"`
+	Touristic types.Bool                       `tfsdk:"touristic"`
+	Cities    []CityStatisticsDataSourceEntity `tfsdk:"cities"`
+}
+
+type CityStatisticsDataSourceEntity struct {
+	Id                           types.Int64   `tfsdk:"id"`
+	Name                         types.String  `tfsdk:"name"`
+	Touristic                    types.Bool    `tfsdk:"touristic"`
+	HouseCount                   types.Int64   `tfsdk:"house_count"`
+	TotalInhabitants             types.Int64   `tfsdk:"total_inhabitants"`
+	AverageInhabitants           types.Float64 `tfsdk:"average_inhabitants"`
+	LargestHouseId               types.Int64   `tfsdk:"largest_house_id"`
+	LargestHouseInhabitants      types.Int64   `tfsdk:"largest_house_inhabitants"`
+	StoreCount                   types.Int64   `tfsdk:"store_count"`
+	StoreCountByType             types.Map     `tfsdk:"store_count_by_type"`
+	StoresPerThousandInhabitants types.Float64 `tfsdk:"stores_per_thousand_inhabitants"`
+}
+
+func (d *CityStatisticsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_city_statistics"
+}
+
+func (d *CityStatisticsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "City statistics data source",
+
+		Attributes: map[string]schema.Attribute{
+			"city_id": schema.Int64Attribute{
+				MarkdownDescription: "City identifier, statistics are computed for every city when not set",
+				Optional:            true,
+				Validators:          idValidators(),
+			},
+			"touristic": schema.BoolAttribute{
+				MarkdownDescription: "Only compute statistics for touristic or non touristic cities",
+				Optional:            true,
+			},
+			"cities": schema.ListNestedAttribute{
+				MarkdownDescription: "Per city statistics",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							MarkdownDescription: "City identifier",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "City name",
+							Computed:            true,
+						},
+						"touristic": schema.BoolAttribute{
+							MarkdownDescription: "Whether the city is touristic or not",
+							Computed:            true,
+						},
+						"house_count": schema.Int64Attribute{
+							MarkdownDescription: "Number of houses in the city",
+							Computed:            true,
+						},
+						"total_inhabitants": schema.Int64Attribute{
+							MarkdownDescription: "Sum of the inhabitants of the city houses",
+							Computed:            true,
+						},
+						"average_inhabitants": schema.Float64Attribute{
+							MarkdownDescription: "Average inhabitants per house, null when the city has no house",
+							Computed:            true,
+						},
+						"largest_house_id": schema.Int64Attribute{
+							MarkdownDescription: "Identifier of the house with the most inhabitants",
+							Computed:            true,
+						},
+						"largest_house_inhabitants": schema.Int64Attribute{
+							MarkdownDescription: "Inhabitants count of the house with the most inhabitants",
+							Computed:            true,
+						},
+						"store_count": schema.Int64Attribute{
+							MarkdownDescription: "Number of stores in the city",
+							Computed:            true,
+						},
+						"store_count_by_type": schema.MapAttribute{
+							MarkdownDescription: "Number of stores in the city per store type",
+							ElementType:         types.Int64Type,
+							Computed:            true,
+						},
+						"stores_per_thousand_inhabitants": schema.Float64Attribute{
+							MarkdownDescription: "Number of stores per 1,000 inhabitants, null when the city has no inhabitant",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *CityStatisticsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
+				"Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+	d.nameAffixes = providerData.NameAffixes
+}
+
+func (d *CityStatisticsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CityStatisticsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var cities []City
+	var houses []House
+	var stores []Store
+	errs := runConcurrently(3, maxConcurrentRequests, func(i int) (err error) {
+		switch i {
+		case 0:
+			if data.CityId.IsNull() {
+				cities, err = listCities(d.client, nil)
+				return err
+			}
+			var city *City
+			city, err = readCity(d.client, strconv.FormatInt(data.CityId.ValueInt64(), 10))
+			if err == nil && city == nil {
+				err = fmt.Errorf("city with id %d does not exist", data.CityId.ValueInt64())
+			}
+			if city != nil {
+				cities = []City{*city}
+			}
+			return err
+		case 1:
+			if data.CityId.IsNull() {
+				houses, err = listHouses(d.client, nil)
+			} else {
+				houses, err = listCityHouses(d.client, int(data.CityId.ValueInt64()))
+			}
+		default:
+			if data.CityId.IsNull() {
+				stores, err = listStores(d.client, nil)
+			} else {
+				stores, err = listCityStores(d.client, int(data.CityId.ValueInt64()))
+			}
+		}
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to compute city statistics, got error: %s", err))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	housesByCity := make(map[int][]House)
+	for _, house := range houses {
+		housesByCity[house.CityId] = append(housesByCity[house.CityId], house)
+	}
+	storesByCity := make(map[int][]Store)
+	for _, store := range stores {
+		storesByCity[store.CityId] = append(storesByCity[store.CityId], store)
+	}
+
+	sort.SliceStable(cities, func(i, j int) bool {
+		return createdBefore(cities[i].Timestamp, cities[i].Id, cities[j].Timestamp, cities[j].Id)
+	})
+
+	data.Cities = []CityStatisticsDataSourceEntity{}
+	for _, city := range cities {
+		touristic := valueOf(city.Touristic)
+		if !data.Touristic.IsNull() && data.Touristic.ValueBool() != touristic {
+			continue
+		}
+
+		city.Name = d.nameAffixes.configName(city.Name)
+		entity, diags := newCityStatistics(ctx, city, housesByCity[city.Id], storesByCity[city.Id])
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.Cities = append(data.Cities, entity)
+	}
+
+	tflog.Trace(ctx, "read city statistics from a data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// newCityStatistics aggregates the given houses and stores of a city.
+func newCityStatistics(ctx context.Context, city City, houses []House, stores []Store) (CityStatisticsDataSourceEntity, diag.Diagnostics) {
+	entity := CityStatisticsDataSourceEntity{
+		Id:                           types.Int64Value(int64(city.Id)),
+		Name:                         types.StringValue(city.Name),
+		Touristic:                    types.BoolValue(city.Touristic != nil && *city.Touristic),
+		HouseCount:                   types.Int64Value(int64(len(houses))),
+		AverageInhabitants:           types.Float64Null(),
+		LargestHouseId:               types.Int64Null(),
+		LargestHouseInhabitants:      types.Int64Null(),
+		StoreCount:                   types.Int64Value(int64(len(stores))),
+		StoresPerThousandInhabitants: types.Float64Null(),
+	}
+
+	total := 0
+	var largest *House
+	for i, house := range houses {
+		total += valueOf(house.Inhabitants)
+		if largest == nil || valueOf(house.Inhabitants) > valueOf(largest.Inhabitants) {
+			largest = &houses[i]
+		}
+	}
+	entity.TotalInhabitants = types.Int64Value(int64(total))
+	if largest != nil {
+		entity.AverageInhabitants = types.Float64Value(float64(total) / float64(len(houses)))
+		entity.LargestHouseId = types.Int64Value(int64(largest.Id))
+		entity.LargestHouseInhabitants = int64Value(largest.Inhabitants)
+	}
+	if total > 0 {
+		entity.StoresPerThousandInhabitants = types.Float64Value(float64(len(stores)) * 1000 / float64(total))
+	}
+
+	countByType := make(map[string]int64)
+	for _, store := range stores {
+		countByType[store.Type]++
+	}
+	storeCountByType, diags := types.MapValueFrom(ctx, types.Int64Type, countByType)
+	entity.StoreCountByType = storeCountByType
+
+	return entity, diags
+}
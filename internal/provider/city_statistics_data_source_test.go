@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCityStatisticsDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccCityStatisticsDataSourceConfig("data-city-statistics-test"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sendoracity_city_statistics.test", "cities.#", "1"),
+					resource.TestCheckResourceAttr("data.sendoracity_city_statistics.test", "cities.0.name", "data-city-statistics-test"),
+					resource.TestCheckResourceAttr("data.sendoracity_city_statistics.test", "cities.0.house_count", "2"),
+					resource.TestCheckResourceAttr("data.sendoracity_city_statistics.test", "cities.0.total_inhabitants", "500"),
+					resource.TestCheckResourceAttr("data.sendoracity_city_statistics.test", "cities.0.average_inhabitants", "250"),
+					resource.TestCheckResourceAttrPair("data.sendoracity_city_statistics.test", "cities.0.largest_house_id", "sendoracity_house.large", "id"),
+					resource.TestCheckResourceAttr("data.sendoracity_city_statistics.test", "cities.0.store_count", "1"),
+					resource.TestCheckResourceAttr("data.sendoracity_city_statistics.test", "cities.0.store_count_by_type.Food", "1"),
+					resource.TestCheckResourceAttr("data.sendoracity_city_statistics.test", "cities.0.stores_per_thousand_inhabitants", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCityStatisticsDataSourceConfig(name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "sendoracity_house" "small" {
+  city_id     = sendoracity_city.test.id
+  address     = "data-city-statistics-test-small"
+  inhabitants = 100
+}
+
+resource "sendoracity_house" "large" {
+  city_id     = sendoracity_city.test.id
+  address     = "data-city-statistics-test-large"
+  inhabitants = 400
+}
+
+resource "sendoracity_store" "test" {
+  city_id = sendoracity_city.test.id
+  address = "data-city-statistics-test-store"
+  name    = "Store 1"
+  type    = "Food"
+}
+
+data "sendoracity_city_statistics" "test" {
+	city_id = sendoracity_city.test.id
+
+	depends_on = [sendoracity_house.small, sendoracity_house.large, sendoracity_store.test]
+}
+`, testAccCityResourceConfig(name))
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxConcurrentRequests bounds the number of API requests issued in parallel
+// by a single resource or data source operation.
+const maxConcurrentRequests = 4
+
+// runConcurrently calls fn for every index in [0, count) with at most limit
+// calls in flight, and returns the error of each call at its index.
+func runConcurrently(count, limit int, fn func(i int) error) []error {
+	errs := make([]error, count)
+	semaphore := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// retryAttempts and retryDelay control how failed API requests are retried.
+const (
+	retryAttempts = 3
+	retryDelay    = time.Second
+)
+
+// retry calls fn until it succeeds or retryAttempts is reached, doubling the
+// delay between each attempt, and returns the last error. Waiting stops when
+// ctx is cancelled.
+func retry(ctx context.Context, fn func() error) error {
+	var err error
+	delay := retryDelay
+	for i := 0; i < retryAttempts; i++ {
+		if i > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("%w, last error: %s", ctx.Err(), err)
+			case <-timer.C:
+			}
+			delay *= 2
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// keyedMutex provides a mutex per key, created on first use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock locks the mutex of the given key, and returns the function unlocking it.
+func (m *keyedMutex) lock(key string) func() {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*sync.Mutex)
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		m.locks[key] = l
+	}
+	m.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// deletionProtectionAttribute returns the deletion_protection schema attribute
+// shared by every resource.
+func deletionProtectionAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+		MarkdownDescription: "Prevent the resource from being destroyed",
+	}
+}
+
+// deletionProtectionError returns the error raised when deleting a protected
+// resource.
+func deletionProtectionError(kind, id string) diag.Diagnostic {
+	return diag.NewErrorDiagnostic("Deletion Protection Enabled",
+		fmt.Sprintf("Cannot delete %s with id %s because deletion_protection is enabled. "+
+			"Set deletion_protection = false and apply the change before destroying it.", kind, id))
+}
+
+// modifyPlanDeletionProtection warns ahead of time when the plan destroys a
+// resource with deletion protection enabled, or replaces it as reported by
+// replaced. Replacements required by attribute plan modifiers are not visible
+// in resp.RequiresReplace, which only holds those of the resource ModifyPlan,
+// so the caller determines them.
+func modifyPlanDeletionProtection(ctx context.Context, kind string, replaced bool, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || (!req.Plan.Raw.IsNull() && !replaced) {
+		return
+	}
+
+	var protected types.Bool
+	var id types.Int64
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("deletion_protection"), &protected)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() || !protected.ValueBool() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning("Deletion Protection Enabled",
+		fmt.Sprintf("This plan destroys %s with id %d, which has deletion_protection enabled: the apply will fail. "+
+			"Set deletion_protection = false and apply the change first.", kind, id.ValueInt64()))
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -26,10 +25,11 @@ type HouseDataSource struct {
 }
 
 type HouseDataSourceModel struct {
-	Id          types.String `tfsdk:"id"`
-	CityId      types.String `tfsdk:"city_id"`
+	Id          types.Int64  `tfsdk:"id"`
+	CityId      types.Int64  `tfsdk:"city_id"`
 	Address     types.String `tfsdk:"address"`
 	Inhabitants types.Int64  `tfsdk:"inhabitants"`
+	CreatedAt   types.String `tfsdk:"created_at"`
 }
 
 func (d *HouseDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -41,11 +41,12 @@ func (d *HouseDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 		MarkdownDescription: "House data source",
 
 		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
+			"id": schema.Int64Attribute{
 				MarkdownDescription: "House identifier",
 				Required:            true,
+				Validators:          idValidators(),
 			},
-			"city_id": schema.StringAttribute{
+			"city_id": schema.Int64Attribute{
 				MarkdownDescription: "House city identifier",
 				Computed:            true,
 			},
@@ -57,6 +58,10 @@ func (d *HouseDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 				MarkdownDescription: "House inhabitants count",
 				Computed:            true,
 			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "House creation timestamp (RFC3339)",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -66,18 +71,18 @@ func (d *HouseDataSource) Configure(ctx context.Context, req datasource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SendoraCityClient)
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.SendoraCityClient, got: %T."+
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
 				"Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = providerData.Client
 	d.url = "houses"
 }
 
@@ -90,7 +95,7 @@ func (d *HouseDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	res, err := d.client.DoRead(fmt.Sprintf("%s/%s", d.url, data.Id.ValueString()))
+	res, err := d.client.DoRead(fmt.Sprintf("%s/%d", d.url, data.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to read house, got error: %s", err))
@@ -112,9 +117,10 @@ func (d *HouseDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	data.CityId = types.StringValue(strconv.Itoa(house.CityId))
+	data.CityId = types.Int64Value(int64(house.CityId))
 	data.Address = types.StringValue(house.Address)
-	data.Inhabitants = types.Int64Value(int64(house.Inhabitants))
+	data.Inhabitants = int64Value(house.Inhabitants)
+	data.CreatedAt = createdAtValue(house.Timestamp)
 
 	tflog.Trace(ctx, "read house from a data source")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
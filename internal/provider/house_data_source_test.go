@@ -18,6 +18,7 @@ func TestAccHouseDataSource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("sendoracity_house.test", "id"),
 					resource.TestCheckResourceAttrSet("sendoracity_house.test", "city_id"),
+					resource.TestCheckResourceAttrSet("data.sendoracity_house.test", "created_at"),
 					resource.TestCheckResourceAttr("sendoracity_house.test", "address", "data-house-test-address"),
 					resource.TestCheckResourceAttr("sendoracity_house.test", "inhabitants", "2"),
 				),
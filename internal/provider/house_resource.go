@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -20,21 +21,39 @@ import (
 
 var _ resource.Resource = &HouseResource{}
 var _ resource.ResourceWithImportState = &HouseResource{}
+var _ resource.ResourceWithUpgradeState = &HouseResource{}
+var _ resource.ResourceWithModifyPlan = &HouseResource{}
 
 func NewHouseResource() resource.Resource {
 	return &HouseResource{}
 }
 
 type HouseResource struct {
-	client *client.SendoraCityClient
-	url    string
+	client                 *client.SendoraCityClient
+	url                    string
+	relocationMode         string
+	maxInhabitants         int64
+	addressNormalization   addressNormalization
+	enforceUniqueAddresses bool
+	defaultCityId          types.Int64
+	nameAffixes            nameAffixes
+	addressType            AddressType
 }
 
 type HouseResourceModel struct {
-	Id          types.String `tfsdk:"id"`
-	CityId      types.String `tfsdk:"city_id"`
-	Address     types.String `tfsdk:"address"`
-	Inhabitants types.Int64  `tfsdk:"inhabitants"`
+	Id                 types.Int64  `tfsdk:"id"`
+	CityId             types.Int64  `tfsdk:"city_id"`
+	Address            AddressValue `tfsdk:"address"`
+	NormalizedAddress  types.String `tfsdk:"normalized_address"`
+	StreetNumber       types.String `tfsdk:"street_number"`
+	StreetType         types.String `tfsdk:"street_type"`
+	StreetName         types.String `tfsdk:"street_name"`
+	PostalCode         types.String `tfsdk:"postal_code"`
+	Inhabitants        types.Int64  `tfsdk:"inhabitants"`
+	CreatedAt          types.String `tfsdk:"created_at"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+	AdoptExisting      types.Bool   `tfsdk:"adopt_existing"`
+	RelocationMode     types.String `tfsdk:"relocation_mode"`
 }
 
 func (r *HouseResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -44,48 +63,99 @@ func (r *HouseResource) Metadata(ctx context.Context, req resource.MetadataReque
 func (r *HouseResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "House resource",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
+			"id": schema.Int64Attribute{
 				Computed:            true,
 				MarkdownDescription: "House identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "House creation timestamp (RFC3339)",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"city_id": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "House city identifier",
+			"city_id": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "House city identifier. Defaults to the provider `default_city_id`",
+				PlanModifiers: []planmodifier.Int64{
+					relocationModePlanModifier{},
+				},
+				Validators: idValidators(),
 			},
 			"address": schema.StringAttribute{
+				CustomType:          r.addressType,
 				Required:            true,
-				MarkdownDescription: "House address",
+				MarkdownDescription: "House address. Changes made outside of Terraform that keep the same `normalized_address` are ignored",
+				Validators:          textValidators(),
+			},
+			"normalized_address": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "House address in its canonical form, following the provider `address_normalization`",
+			},
+			"street_number": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Street number parsed from the address, with its repetition index such as `bis`",
+			},
+			"street_type": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Street type parsed from the address, in its full form, lowercase and without accents, such as `avenue`",
+			},
+			"street_name": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Street name parsed from the address",
+			},
+			"postal_code": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Postal code parsed from the address, when present",
 			},
 			"inhabitants": schema.Int64Attribute{
 				Required:            true,
 				MarkdownDescription: "House inhabitants count",
+				Validators:          inhabitantsValidators(),
 			},
+			"deletion_protection": deletionProtectionAttribute(),
+			"adopt_existing":      adoptExistingAttribute(),
+			"relocation_mode":     relocationModeAttribute(),
 		},
 	}
 }
 
+func (r *HouseResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: idsToInt64StateUpgrader([]string{"id", "city_id"}, nil),
+	}
+}
+
 func (r *HouseResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SendoraCityClient)
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.SendoraCityClient, got: %T."+
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
 				"Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Client
+	r.relocationMode = providerData.RelocationMode
+	r.addressNormalization = providerData.AddressNormalization
+	r.enforceUniqueAddresses = providerData.EnforceUniqueAddresses
+	r.defaultCityId = providerData.DefaultCityId
+	r.nameAffixes = providerData.NameAffixes
+	r.maxInhabitants = providerData.MaxInhabitants
 	r.url = "houses"
 }
 
@@ -98,17 +168,30 @@ func (r *HouseResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	cityId, err := strconv.Atoi(data.CityId.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error",
-			fmt.Sprintf("Unable to convert city_id to int, got error: %s", err))
-		return
-	}
-
-	body := &House{
-		CityId:      cityId,
-		Address:     data.Address.ValueString(),
-		Inhabitants: int(data.Inhabitants.ValueInt64()),
+	body := houseBody(data)
+
+	if data.AdoptExisting.ValueBool() {
+		existing, err := adoptableHouse(r.client, body.CityId, body.Address)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to look up an existing house to adopt, got error: %s", err))
+			return
+		}
+		if existing != nil {
+			id := strconv.Itoa(existing.Id)
+			if err = updateObject(r.client, fmt.Sprintf("%s/%s", r.url, id), body); err != nil {
+				resp.Diagnostics.AddError("Client Error",
+					fmt.Sprintf("Unable to update adopted house with id %s, got error: %s", id, err))
+				return
+			}
+
+			data.Id = types.Int64Value(int64(existing.Id))
+			data.CreatedAt = createdAtValue(existing.Timestamp)
+
+			tflog.Trace(ctx, "adopted an existing house resource", map[string]any{"id": id})
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
 	}
 
 	jsonBody, err := json.Marshal(body)
@@ -140,7 +223,8 @@ func (r *HouseResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	data.Id = types.StringValue(strconv.Itoa(house.Id))
+	data.Id = types.Int64Value(int64(house.Id))
+	data.CreatedAt = createdAtValue(house.Timestamp)
 
 	tflog.Trace(ctx, "created a house resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -155,7 +239,7 @@ func (r *HouseResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	res, err := r.client.DoRead(fmt.Sprintf("%s/%s", r.url, data.Id.ValueString()))
+	res, err := r.client.DoRead(fmt.Sprintf("%s/%d", r.url, data.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to read house, got error: %s", err))
@@ -182,9 +266,18 @@ func (r *HouseResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	data.CityId = types.StringValue(strconv.Itoa(house.CityId))
-	data.Address = types.StringValue(house.Address)
-	data.Inhabitants = types.Int64Value(int64(house.Inhabitants))
+	priorAddress := data.Address
+	refreshHouseModel(data, house)
+	address, diags := keptAddress(ctx, priorAddress, data.Address)
+	resp.Diagnostics.Append(diags...)
+	data.NormalizedAddress = r.addressNormalization.normalizedAddressValue(address.StringValue)
+	data.StreetNumber, data.StreetType, data.StreetName, data.PostalCode = addressComponentValues(address.StringValue)
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
+	}
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -198,18 +291,7 @@ func (r *HouseResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	cityId, err := strconv.Atoi(data.CityId.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error",
-			fmt.Sprintf("Unable to convert city_id to int, got error: %s", err))
-		return
-	}
-
-	body := &House{
-		CityId:      cityId,
-		Address:     data.Address.ValueString(),
-		Inhabitants: int(data.Inhabitants.ValueInt64()),
-	}
+	body := houseBody(data)
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
@@ -218,7 +300,7 @@ func (r *HouseResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	id := data.Id.ValueString()
+	id := strconv.FormatInt(data.Id.ValueInt64(), 10)
 	if _, err = r.client.DoUpdate(fmt.Sprintf("%s/%s", r.url, id), jsonBody); err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to update house with id %s, got error: %s", id, err))
@@ -237,7 +319,12 @@ func (r *HouseResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	id := data.Id.ValueString()
+	id := strconv.FormatInt(data.Id.ValueInt64(), 10)
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.Append(deletionProtectionError("house", id))
+		return
+	}
+
 	if _, err := r.client.DoDelete(fmt.Sprintf("%s/%s", r.url, id)); err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to delete house with id %s, got error: %s", id, err))
@@ -245,6 +332,29 @@ func (r *HouseResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 }
 
+func (r *HouseResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanDefaultCityId(ctx, r.defaultCityId, req, resp)
+	replaced := modifyPlanRelocation(ctx, r.relocationMode, req, resp)
+	modifyPlanDeletionProtection(ctx, "house", replaced, req, resp)
+	modifyPlanAddress(ctx, r.addressNormalization, req, resp)
+	if r.enforceUniqueAddresses {
+		modifyPlanUniqueAddress(ctx, r.client, r.addressNormalization, nameAffixes{}, "house", req, resp)
+	}
+
+	if !req.Plan.Raw.IsNull() {
+		var inhabitants types.Int64
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("inhabitants"), &inhabitants)...)
+		resp.Diagnostics.Append(validateMaxInhabitants(r.maxInhabitants, path.Root("inhabitants"), inhabitants)...)
+	}
+}
+
 func (r *HouseResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id, err := resolveImportId(r.client, r.nameAffixes, "house", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import Identifier",
+			fmt.Sprintf("Unable to import house, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), int64(id))...)
 }
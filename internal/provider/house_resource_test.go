@@ -2,9 +2,11 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccHouseResource(t *testing.T) {
@@ -17,8 +19,12 @@ func TestAccHouseResource(t *testing.T) {
 				Config: testAccHouseResourceConfig("house-test-city-name", "house-test-address-1"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("sendoracity_house.test", "id"),
+					resource.TestCheckResourceAttrSet("sendoracity_house.test", "created_at"),
 					resource.TestCheckResourceAttrSet("sendoracity_house.test", "city_id"),
 					resource.TestCheckResourceAttr("sendoracity_house.test", "address", "house-test-address-1"),
+					resource.TestCheckResourceAttr("sendoracity_house.test", "normalized_address", "house test address 1"),
+					resource.TestCheckResourceAttr("sendoracity_house.test", "street_name", "house-test-address-1"),
+					resource.TestCheckNoResourceAttr("sendoracity_house.test", "street_number"),
 					resource.TestCheckResourceAttr("sendoracity_house.test", "inhabitants", "2"),
 				),
 			},
@@ -28,6 +34,13 @@ func TestAccHouseResource(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			// ImportState by natural key testing
+			{
+				ResourceName:      "sendoracity_house.test",
+				ImportState:       true,
+				ImportStateId:     "house:house-test-city-name/house-test-address-1",
+				ImportStateVerify: true,
+			},
 			// Update and Read testing
 			{
 				Config: testAccHouseResourceConfig("house-test-city-name", "house-test-address-2"),
@@ -54,3 +67,220 @@ resource "sendoracity_house" "test" {
 }
 `, cityName, address)
 }
+
+func TestAccHouseResourceAdoptExisting(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create a city, then a house outside of Terraform
+			{
+				Config: testAccHouseResourceAdoptExistingConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCreateUnmanagedHouse("sendoracity_city.test", "house-test-adopt-address"),
+				),
+			},
+			// Adopt the existing house and update it to the planned values
+			{
+				Config: testAccHouseResourceAdoptExistingConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sendoracity_house.test", "id"),
+					resource.TestCheckResourceAttr("sendoracity_house.test", "adopt_existing", "true"),
+					resource.TestCheckResourceAttr("sendoracity_house.test", "inhabitants", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccHouseResourceAdoptExistingConfig(withHouse bool) string {
+	config := `
+resource "sendoracity_city" "test" {
+  name          = "house-test-adopt-city"
+  touristic     = false
+  force_destroy = true
+}
+`
+	if withHouse {
+		config += `
+resource "sendoracity_house" "test" {
+  city_id        = sendoracity_city.test.id
+  address        = "house-test-adopt-address"
+  inhabitants    = 2
+  adopt_existing = true
+}
+`
+	}
+	return config
+}
+
+func TestAccHouseResourceRelocationReplace(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create the house in the first city
+			{
+				Config: testAccHouseResourceRelocationConfig("first"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("sendoracity_house.test", "city_id", "sendoracity_city.first", "id"),
+					testAccSaveResourceAttr("sendoracity_house.test", "id", &id),
+				),
+			},
+			// Moving the house to the second city replaces it
+			{
+				Config: testAccHouseResourceRelocationConfig("second"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("sendoracity_house.test", "city_id", "sendoracity_city.second", "id"),
+					func(s *terraform.State) error {
+						if s.RootModule().Resources["sendoracity_house.test"].Primary.ID == id {
+							return fmt.Errorf("house %s was updated in place instead of being replaced", id)
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccHouseResourceRelocationConfig(city string) string {
+	return fmt.Sprintf(`
+resource "sendoracity_city" "first" {
+  name      = "house-test-relocation-first"
+  touristic = false
+}
+
+resource "sendoracity_city" "second" {
+  name      = "house-test-relocation-second"
+  touristic = false
+}
+
+resource "sendoracity_house" "test" {
+  city_id         = sendoracity_city.%s.id
+  address         = "house-test-relocation-address"
+  inhabitants     = 2
+  relocation_mode = "replace"
+}
+`, city)
+}
+
+func TestAccHouseResourceUniqueAddress(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create the house
+			{
+				Config: testAccHouseResourceUniqueAddressConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sendoracity_house.test", "id"),
+				),
+			},
+			// A store at the same address, written differently, is rejected
+			{
+				Config: testAccHouseResourceUniqueAddressConfig(`
+resource "sendoracity_store" "test" {
+  city_id = sendoracity_city.test.id
+  address = "1 AV. house-test-unique-address"
+  name    = "Store 1"
+  type    = "Other"
+}
+`),
+				ExpectError: regexp.MustCompile(`already used by house [0-9]+ at "1 avenue house-test-unique-address"`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccHouseResourceUniqueAddressConfig(extra string) string {
+	return fmt.Sprintf(`
+provider "sendoracity" {
+  enforce_unique_addresses = true
+}
+
+resource "sendoracity_city" "test" {
+  name      = "house-test-unique-address"
+  touristic = false
+}
+
+resource "sendoracity_house" "test" {
+  city_id     = sendoracity_city.test.id
+  address     = "1 avenue house-test-unique-address"
+  inhabitants = 2
+}
+%s`, extra)
+}
+
+// testAccSaveResourceAttr saves the value of a resource attribute into target.
+func testAccSaveResourceAttr(resourceName, attribute string, target *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		res, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+		*target = res.Primary.Attributes[attribute]
+		return nil
+	}
+}
+
+func TestAccHouseResourceDeletionProtection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create a protected house
+			{
+				Config: testAccHouseResourceDeletionProtectionConfig("first", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_house.test", "deletion_protection", "true"),
+				),
+			},
+			// Replacing the protected house fails
+			{
+				Config:      testAccHouseResourceDeletionProtectionConfig("second", true),
+				ExpectError: regexp.MustCompile("Deletion Protection Enabled"),
+			},
+			// Destroying the protected house fails
+			{
+				Config:      testAccHouseResourceDeletionProtectionConfig("first", true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Deletion Protection Enabled"),
+			},
+			// Disable the protection so the house can be deleted
+			{
+				Config: testAccHouseResourceDeletionProtectionConfig("first", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_house.test", "deletion_protection", "false"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccHouseResourceDeletionProtectionConfig(city string, protected bool) string {
+	return fmt.Sprintf(`
+resource "sendoracity_city" "first" {
+  name      = "house-test-deletion-protection-first"
+  touristic = false
+}
+
+resource "sendoracity_city" "second" {
+  name      = "house-test-deletion-protection-second"
+  touristic = false
+}
+
+resource "sendoracity_house" "test" {
+  city_id             = sendoracity_city.%s.id
+  address             = "house-test-deletion-protection-address"
+  inhabitants         = 2
+  relocation_mode     = "replace"
+  deletion_protection = %t
+}
+`, city, protected)
+}
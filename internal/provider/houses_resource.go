@@ -0,0 +1,463 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+var _ resource.Resource = &HousesResource{}
+var _ resource.ResourceWithImportState = &HousesResource{}
+var _ resource.ResourceWithModifyPlan = &HousesResource{}
+var _ resource.ResourceWithUpgradeState = &HousesResource{}
+
+func NewHousesResource() resource.Resource {
+	return &HousesResource{}
+}
+
+type HousesResource struct {
+	client         *client.SendoraCityClient
+	url            string
+	maxInhabitants int64
+}
+
+type HousesResourceModel struct {
+	Id     types.Int64                   `tfsdk:"id"`
+	CityId types.Int64                   `tfsdk:"city_id"`
+	Houses map[string]HousesResourceItem `tfsdk:"houses"`
+}
+
+type HousesResourceItem struct {
+	Id          types.Int64  `tfsdk:"id"`
+	Inhabitants types.Int64  `tfsdk:"inhabitants"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+}
+
+func (r *HousesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_houses"
+}
+
+func (r *HousesResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Houses of a city, managed in bulk",
+		Version:             1,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Houses identifier, same as the city identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"city_id": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "Houses city identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: idValidators(),
+			},
+			"houses": schema.MapNestedAttribute{
+				Required:            true,
+				MarkdownDescription: "Houses of the city, keyed by address",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "House identifier",
+							PlanModifiers: []planmodifier.Int64{
+								int64planmodifier.UseStateForUnknown(),
+							},
+						},
+						"inhabitants": schema.Int64Attribute{
+							Required:            true,
+							MarkdownDescription: "House inhabitants count",
+							Validators:          inhabitantsValidators(),
+						},
+						"created_at": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "House creation timestamp (RFC3339)",
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.UseStateForUnknown(),
+							},
+						},
+					},
+				},
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(textValidators()...),
+				},
+			},
+		},
+	}
+}
+
+func (r *HousesResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: idsToInt64StateUpgrader([]string{"id", "city_id"}, []string{"houses"}),
+	}
+}
+
+func (r *HousesResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
+				"Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.maxInhabitants = providerData.MaxInhabitants
+	r.url = "houses"
+}
+
+func (r *HousesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *HousesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, data, nil)...)
+
+	tflog.Trace(ctx, "created a houses resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HousesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *HousesResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	city, err := readCity(r.client, strconv.FormatInt(data.CityId.ValueInt64(), 10))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to read city, got error: %s", err))
+		return
+	}
+	if city == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	houses, err := listCityHouses(r.client, city.Id)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to list houses of city %d, got error: %s", city.Id, err))
+		return
+	}
+
+	housesById := make(map[int64]House)
+	for _, house := range houses {
+		housesById[int64(house.Id)] = house
+	}
+
+	// Houses deleted or moved to another address outside of Terraform are
+	// dropped, so that they get created again on the next apply.
+	items := make(map[string]HousesResourceItem)
+	for address, item := range data.Houses {
+		house, ok := housesById[item.Id.ValueInt64()]
+		if !ok || house.Address != address {
+			continue
+		}
+		items[address] = HousesResourceItem{
+			Id:          item.Id,
+			Inhabitants: int64Value(house.Inhabitants),
+			CreatedAt:   createdAtValue(house.Timestamp),
+		}
+	}
+	data.Houses = items
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HousesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state *HousesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, data, state.Houses)...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HousesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *HousesResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	addresses := sortedKeys(data.Houses)
+	errs := runConcurrently(len(addresses), maxConcurrentRequests, func(i int) error {
+		id := data.Houses[addresses[i]].Id.ValueInt64()
+		return retry(ctx, func() error {
+			return deleteObject(r.client, fmt.Sprintf("%s/%d", r.url, id))
+		})
+	})
+
+	// Keep the houses that could not be deleted in state
+	remaining := make(map[string]HousesResourceItem)
+	for i, err := range errs {
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to delete house %q, got error: %s", addresses[i], err))
+			remaining[addresses[i]] = data.Houses[addresses[i]]
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		data.Houses = remaining
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	}
+}
+
+func (r *HousesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.maxInhabitants <= 0 {
+		return
+	}
+
+	var planned types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("houses"), &planned)...)
+	if resp.Diagnostics.HasError() || planned.IsUnknown() {
+		return
+	}
+
+	houses := map[string]HousesResourceItem{}
+	resp.Diagnostics.Append(planned.ElementsAs(ctx, &houses, false)...)
+	for _, address := range sortedKeys(houses) {
+		resp.Diagnostics.Append(validateMaxInhabitants(r.maxInhabitants,
+			path.Root("houses").AtMapKey(address).AtName("inhabitants"), houses[address].Inhabitants)...)
+	}
+}
+
+func (r *HousesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	cityId, err := strconv.Atoi(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import Identifier",
+			fmt.Sprintf("Expected a city identifier, got: %s", req.ID))
+		return
+	}
+
+	houses, err := listCityHouses(r.client, cityId)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to list houses of city %d, got error: %s", cityId, err))
+		return
+	}
+
+	items := make(map[string]HousesResourceItem)
+	for _, house := range houses {
+		items[house.Address] = HousesResourceItem{
+			Id:          types.Int64Value(int64(house.Id)),
+			Inhabitants: int64Value(house.Inhabitants),
+			CreatedAt:   createdAtValue(house.Timestamp),
+		}
+	}
+
+	data := &HousesResourceModel{
+		Id:     types.Int64Value(int64(cityId)),
+		CityId: types.Int64Value(int64(cityId)),
+		Houses: items,
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// housesOperation is a single house creation, update or deletion computed by
+// diffing the planned houses against the API.
+type housesOperation struct {
+	address string
+	method  string
+	house   House
+}
+
+// apply creates, updates and deletes houses so that the city matches the plan.
+// The model is updated with the outcome of every successful operation, and
+// keeps the prior values of failed ones so that partial progress is saved.
+func (r *HousesResource) apply(ctx context.Context, data *HousesResourceModel, prior map[string]HousesResourceItem) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	cityId := int(data.CityId.ValueInt64())
+	houses, err := listCityHouses(r.client, cityId)
+	if err != nil {
+		diags.AddError("Client Error",
+			fmt.Sprintf("Unable to list houses of city %d, got error: %s", cityId, err))
+		return diags
+	}
+
+	// Only the houses recorded in state are managed. Other houses at a planned
+	// address are conflicts rather than being taken over, as destroying the
+	// resource would delete them.
+	housesByAddress := make(map[string]House)
+	for address, item := range prior {
+		for _, house := range houses {
+			if int64(house.Id) == item.Id.ValueInt64() && house.Address == address {
+				housesByAddress[address] = house
+			}
+		}
+	}
+	unmanagedByAddress := make(map[string]House)
+	for _, house := range houses {
+		if managed, ok := housesByAddress[house.Address]; !ok || managed.Id != house.Id {
+			unmanagedByAddress[house.Address] = house
+		}
+	}
+
+	operations := []housesOperation{}
+	for _, address := range sortedKeys(data.Houses) {
+		planned := House{
+			CityId:      cityId,
+			Address:     address,
+			Inhabitants: intPointer(data.Houses[address].Inhabitants),
+		}
+		existing, ok := housesByAddress[address]
+		if unmanaged, conflict := unmanagedByAddress[address]; !ok && conflict {
+			diags.AddAttributeError(path.Root("houses").AtMapKey(address), "House Already Exists",
+				fmt.Sprintf("House %d already exists at address %q in city %d and is not managed by this resource. "+
+					"Import the resource or delete the house first.", unmanaged.Id, address, cityId))
+			delete(data.Houses, address)
+			continue
+		}
+		switch {
+		case !ok:
+			operations = append(operations, housesOperation{address: address, method: "create", house: planned})
+		case !equalPointers(existing.Inhabitants, planned.Inhabitants):
+			planned.Id = existing.Id
+			planned.Timestamp = existing.Timestamp
+			operations = append(operations, housesOperation{address: address, method: "update", house: planned})
+		default:
+			data.Houses[address] = HousesResourceItem{
+				Id:          types.Int64Value(int64(existing.Id)),
+				Inhabitants: int64Value(existing.Inhabitants),
+				CreatedAt:   createdAtValue(existing.Timestamp),
+			}
+		}
+	}
+	for _, address := range sortedKeys(prior) {
+		if _, ok := data.Houses[address]; ok {
+			continue
+		}
+		id := int(prior[address].Id.ValueInt64())
+		operations = append(operations, housesOperation{address: address, method: "delete", house: House{Id: id}})
+	}
+
+	// Creations are not retried, as a request failing after the house was
+	// created would otherwise duplicate it.
+	results := make([]House, len(operations))
+	errs := runConcurrently(len(operations), maxConcurrentRequests, func(i int) (err error) {
+		if operations[i].method == "create" {
+			results[i], err = r.applyOperation(operations[i])
+			return err
+		}
+		return retry(ctx, func() (err error) {
+			results[i], err = r.applyOperation(operations[i])
+			return err
+		})
+	})
+
+	for i, operation := range operations {
+		if errs[i] != nil {
+			diags.AddError("Client Error",
+				fmt.Sprintf("Unable to %s house %q, got error: %s", operation.method, operation.address, errs[i]))
+			if previous, ok := prior[operation.address]; ok {
+				data.Houses[operation.address] = previous
+			} else {
+				delete(data.Houses, operation.address)
+			}
+			continue
+		}
+		if operation.method == "delete" {
+			continue
+		}
+		data.Houses[operation.address] = HousesResourceItem{
+			Id:          types.Int64Value(int64(results[i].Id)),
+			Inhabitants: int64Value(results[i].Inhabitants),
+			CreatedAt:   createdAtValue(results[i].Timestamp),
+		}
+	}
+
+	tflog.Debug(ctx, "applied houses operations", map[string]any{"city_id": cityId, "operations": len(operations)})
+	data.Id = types.Int64Value(int64(cityId))
+	return diags
+}
+
+// applyOperation sends a single house operation to the API and returns the
+// resulting house.
+func (r *HousesResource) applyOperation(operation housesOperation) (House, error) {
+	if operation.method == "delete" {
+		return operation.house, deleteObject(r.client, fmt.Sprintf("%s/%d", r.url, operation.house.Id))
+	}
+
+	body := operation.house
+	body.Id = 0
+	body.Timestamp = ""
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return House{}, err
+	}
+
+	if operation.method == "update" {
+		res, err := r.client.DoUpdate(fmt.Sprintf("%s/%d", r.url, operation.house.Id), jsonBody)
+		if err != nil {
+			return House{}, err
+		}
+		res.Body.Close()
+		return operation.house, nil
+	}
+
+	res, err := r.client.DoCreate(r.url, jsonBody)
+	if err != nil {
+		return House{}, err
+	}
+
+	house := House{}
+	if err = decodeResponse(res, &house); err != nil {
+		return House{}, err
+	}
+	return house, nil
+}
+
+// sortedKeys returns the keys of a map in lexical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccHousesResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccHousesResourceConfig("houses-test-city-name", map[string]int{
+					"houses-test-address-1": 1,
+					"houses-test-address-2": 2,
+				}),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("sendoracity_houses.test", "id", "sendoracity_city.test", "id"),
+					resource.TestCheckResourceAttr("sendoracity_houses.test", "houses.%", "2"),
+					resource.TestCheckResourceAttrSet("sendoracity_houses.test", "houses.houses-test-address-1.id"),
+					resource.TestCheckResourceAttr("sendoracity_houses.test", "houses.houses-test-address-2.inhabitants", "2"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "sendoracity_houses.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccHousesResourceConfig("houses-test-city-name", map[string]int{
+					"houses-test-address-2": 20,
+					"houses-test-address-3": 3,
+				}),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_houses.test", "houses.%", "2"),
+					resource.TestCheckNoResourceAttr("sendoracity_houses.test", "houses.houses-test-address-1.id"),
+					resource.TestCheckResourceAttr("sendoracity_houses.test", "houses.houses-test-address-2.inhabitants", "20"),
+					resource.TestCheckResourceAttrSet("sendoracity_houses.test", "houses.houses-test-address-3.id"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccHousesResourceConfig(cityName string, houses map[string]int) string {
+	items := ""
+	for _, address := range sortedKeys(houses) {
+		items += fmt.Sprintf(`
+    %q = { inhabitants = %d }`, address, houses[address])
+	}
+
+	return fmt.Sprintf(`
+resource "sendoracity_city" "test" {
+  name      = "%s"
+  touristic = false
+}
+
+resource "sendoracity_houses" "test" {
+  city_id = sendoracity_city.test.id
+  houses = {%s
+  }
+}
+`, cityName, items)
+}
+
+func TestAccHousesResourceUnmanagedHouse(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A house created outside of the resource is not taken over
+			{
+				Config: `
+resource "sendoracity_city" "test" {
+  name      = "houses-test-unmanaged"
+  touristic = false
+}
+
+resource "sendoracity_house" "unmanaged" {
+  city_id     = sendoracity_city.test.id
+  address     = "houses-test-unmanaged-address"
+  inhabitants = 2
+}
+
+resource "sendoracity_houses" "test" {
+  city_id = sendoracity_city.test.id
+  houses = {
+    "houses-test-unmanaged-address" = { inhabitants = 1 }
+  }
+
+  depends_on = [sendoracity_house.unmanaged]
+}
+`,
+				ExpectError: regexp.MustCompile("is not managed by this resource"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
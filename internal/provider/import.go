@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+// resolveImportId returns the numeric identifier of the object designated by
+// an import identifier. Besides plain numeric identifiers, objects can be
+// designated by natural key:
+//
+//   - city:<city name>
+//   - house:<city name>/<house address>
+//   - store:<city name>/<store name>
+//
+// The city name ends at the last "/", so that city names may contain one while
+// house addresses and store names may not. Names are given as in the
+// configuration, the provider name affixes are added before looking them up.
+// The resolved object is checked to exist.
+func resolveImportId(c *client.SendoraCityClient, names nameAffixes, kind, importId string) (int, error) {
+	if id, err := strconv.Atoi(importId); err == nil {
+		exists, err := objectExists(c, fmt.Sprintf("%s/%d", kindEndpoint(kind), id))
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			return 0, fmt.Errorf("%s with id %d does not exist", kind, id)
+		}
+		return id, nil
+	}
+
+	key := strings.TrimPrefix(importId, kind+":")
+	if key == importId || key == "" {
+		return 0, fmt.Errorf("expected a numeric identifier or %s, got: %s", importFormat(kind), importId)
+	}
+
+	if kind == "city" {
+		city, err := findCityByName(c, names.apiName(key))
+		if err != nil {
+			return 0, err
+		}
+		return city.Id, nil
+	}
+
+	separator := strings.LastIndex(key, "/")
+	if separator < 0 {
+		return 0, fmt.Errorf("expected a numeric identifier or %s, got: %s", importFormat(kind), importId)
+	}
+	cityName, name := key[:separator], key[separator+1:]
+	if cityName == "" || name == "" {
+		return 0, fmt.Errorf("expected a numeric identifier or %s, got: %s", importFormat(kind), importId)
+	}
+
+	city, err := findCityByName(c, names.apiName(cityName))
+	if err != nil {
+		return 0, err
+	}
+
+	ids := []int{}
+	if kind == "house" {
+		houses, err := listCityHouses(c, city.Id)
+		if err != nil {
+			return 0, err
+		}
+		for _, house := range houses {
+			if house.Address == name {
+				ids = append(ids, house.Id)
+			}
+		}
+	} else {
+		stores, err := listCityStores(c, city.Id)
+		if err != nil {
+			return 0, err
+		}
+		for _, store := range stores {
+			if store.Name == names.apiName(name) {
+				ids = append(ids, store.Id)
+			}
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Errorf("no %s %q found in city %q", kind, name, cityName)
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, fmt.Errorf("%q matches %d %ss in city %q (ids %s), import one of them by id",
+			name, len(ids), kind, cityName, joinIds(ids))
+	}
+}
+
+// findCityByName returns the only city with the given name.
+func findCityByName(c *client.SendoraCityClient, name string) (*City, error) {
+	matches, err := citiesNamed(c, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no city named %q found", name)
+	case 1:
+		return &matches[0], nil
+	default:
+		ids := []int{}
+		for _, city := range matches {
+			ids = append(ids, city.Id)
+		}
+		return nil, fmt.Errorf("%q matches %d cities (ids %s), import by id instead",
+			name, len(matches), joinIds(ids))
+	}
+}
+
+// citiesNamed returns the cities with exactly the given name.
+func citiesNamed(c *client.SendoraCityClient, name string) ([]City, error) {
+	cities, err := listCities(c, map[string]string{"name": name})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []City{}
+	for _, city := range cities {
+		if city.Name == name {
+			matches = append(matches, city)
+		}
+	}
+	return matches, nil
+}
+
+// kindEndpoint returns the API endpoint of the given kind of object.
+func kindEndpoint(kind string) string {
+	if kind == "city" {
+		return "cities"
+	}
+	return kind + "s"
+}
+
+// importFormat describes the natural key import identifier of the given kind
+// of object.
+func importFormat(kind string) string {
+	switch kind {
+	case "city":
+		return "city:<name>"
+	case "house":
+		return "house:<city name>/<address>"
+	default:
+		return "store:<city name>/<name>"
+	}
+}
+
+// joinIds formats a list of identifiers for error messages.
+func joinIds(ids []int) string {
+	values := []string{}
+	for _, id := range ids {
+		values = append(values, strconv.Itoa(id))
+	}
+	return strings.Join(values, ", ")
+}
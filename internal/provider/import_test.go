@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+func TestResolveImportId(t *testing.T) {
+	cities := []City{{Id: 1, Name: "Troyes"}, {Id: 2, Name: "Bar/Seine"}, {Id: 3, Name: "test-Paris-ephemeral"}}
+	houses := []House{{Id: 10, CityId: 2, Address: "1 rue Thiers"}}
+	stores := []Store{{Id: 20, CityId: 1, Name: "Cocci Marche"}, {Id: 21, CityId: 3, Name: "test-Cocci Marche-ephemeral"}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+		switch r.URL.Path {
+		case "/cities":
+			matches := []City{}
+			for _, city := range cities {
+				if city.Name == r.URL.Query().Get("name") {
+					matches = append(matches, city)
+				}
+			}
+			body = matches
+		case "/houses":
+			body = houses
+		case "/stores":
+			body = stores
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+	c := client.NewClient(server.URL)
+	affixes := nameAffixes{Prefix: "test-", Suffix: "-ephemeral"}
+
+	tests := []struct {
+		name     string
+		names    nameAffixes
+		kind     string
+		importId string
+		want     int
+		wantErr  string
+	}{
+		{name: "city", kind: "city", importId: "city:Troyes", want: 1},
+		{name: "city with a slash", kind: "city", importId: "city:Bar/Seine", want: 2},
+		{name: "house in a city with a slash", kind: "house", importId: "house:Bar/Seine/1 rue Thiers", want: 10},
+		{name: "store", kind: "store", importId: "store:Troyes/Cocci Marche", want: 20},
+		{name: "city with affixes", names: affixes, kind: "city", importId: "city:Paris", want: 3},
+		{name: "store with affixes", names: affixes, kind: "store", importId: "store:Paris/Cocci Marche", want: 21},
+		{name: "missing city", kind: "city", importId: "city:Paris", wantErr: `no city named "Paris" found`},
+		{name: "missing name", kind: "store", importId: "store:Troyes/", wantErr: "expected a numeric identifier"},
+		{name: "missing separator", kind: "store", importId: "store:Troyes", wantErr: "expected a numeric identifier"},
+		{name: "other kind", kind: "house", importId: "store:Troyes/Cocci Marche", wantErr: "expected a numeric identifier"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := resolveImportId(c, test.names, test.kind, test.importId)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("got error %v, want error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil || got != test.want {
+				t.Errorf("got %d, %v, want %d", got, err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// layoutDocument is the YAML or JSON document describing a city layout, shaped
+// like example/config.yml.
+type layoutDocument struct {
+	Cities []layoutCity `json:"cities" yaml:"cities"`
+}
+
+type layoutCity struct {
+	Name      string        `json:"name" yaml:"name"`
+	Touristic *bool         `json:"touristic" yaml:"touristic"`
+	Houses    []layoutHouse `json:"houses" yaml:"houses"`
+	Stores    []layoutStore `json:"stores" yaml:"stores"`
+}
+
+type layoutHouse struct {
+	Address     string `json:"address" yaml:"address"`
+	Inhabitants *int   `json:"inhabitants" yaml:"inhabitants"`
+}
+
+type layoutStore struct {
+	Name    string `json:"name" yaml:"name"`
+	Address string `json:"address" yaml:"address"`
+	Type    string `json:"type" yaml:"type"`
+}
+
+// parseLayout decodes a layout document in the given format, rejecting unknown
+// fields, and validates it. Store types are checked against storeTypes unless
+// nil, when the store types accepted by the API are not available.
+func parseLayout(document, format string, storeTypes []string) (*layoutDocument, error) {
+	layout := &layoutDocument{}
+	switch format {
+	case "json":
+		decoder := json.NewDecoder(strings.NewReader(document))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(layout); err != nil {
+			return nil, fmt.Errorf("invalid JSON document: %w", err)
+		}
+	case "yaml":
+		decoder := yaml.NewDecoder(bytes.NewBufferString(document))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(layout); err != nil {
+			return nil, fmt.Errorf("invalid YAML document: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	if err := layout.validate(storeTypes); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// validate checks the document against the layout schema and normalizes store
+// types to their canonical casing.
+func (l *layoutDocument) validate(storeTypes []string) error {
+	cityNames := make(map[string]bool)
+	for i := range l.Cities {
+		city := &l.Cities[i]
+		if city.Name == "" {
+			return fmt.Errorf("cities[%d]: name is required", i)
+		}
+		if cityNames[city.Name] {
+			return fmt.Errorf("cities[%d]: duplicate city name %q", i, city.Name)
+		}
+		cityNames[city.Name] = true
+		if city.Touristic == nil {
+			return fmt.Errorf("city %q: touristic is required", city.Name)
+		}
+
+		addresses := make(map[string]bool)
+		for j, house := range city.Houses {
+			if house.Address == "" {
+				return fmt.Errorf("city %q: houses[%d]: address is required", city.Name, j)
+			}
+			if addresses[house.Address] {
+				return fmt.Errorf("city %q: duplicate house address %q", city.Name, house.Address)
+			}
+			addresses[house.Address] = true
+			if house.Inhabitants == nil {
+				return fmt.Errorf("city %q: house %q: inhabitants is required", city.Name, house.Address)
+			}
+			if *house.Inhabitants < 0 {
+				return fmt.Errorf("city %q: house %q: inhabitants must be zero or more", city.Name, house.Address)
+			}
+		}
+
+		addresses = make(map[string]bool)
+		for j := range city.Stores {
+			store := &city.Stores[j]
+			if store.Name == "" || store.Address == "" {
+				return fmt.Errorf("city %q: stores[%d]: name and address are required", city.Name, j)
+			}
+			if addresses[store.Address] {
+				return fmt.Errorf("city %q: duplicate store address %q", city.Name, store.Address)
+			}
+			addresses[store.Address] = true
+			if storeTypes == nil {
+				continue
+			}
+			storeType, ok := canonicalStoreType(store.Type, storeTypes)
+			if !ok {
+				return fmt.Errorf("city %q: store %q: type %s", city.Name, store.Name, storeTypeError(store.Type, storeTypes))
+			}
+			store.Type = storeType
+		}
+	}
+	return nil
+}
@@ -0,0 +1,598 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+var _ resource.Resource = &LayoutResource{}
+var _ resource.ResourceWithModifyPlan = &LayoutResource{}
+var _ resource.ResourceWithValidateConfig = &LayoutResource{}
+var _ resource.ResourceWithUpgradeState = &LayoutResource{}
+
+func NewLayoutResource() resource.Resource {
+	return &LayoutResource{}
+}
+
+type LayoutResource struct {
+	client      *client.SendoraCityClient
+	storeTypes  *storeTypeCatalog
+	nameAffixes nameAffixes
+}
+
+type LayoutResourceModel struct {
+	Id       types.String `tfsdk:"id"`
+	Document types.String `tfsdk:"document"`
+	Format   types.String `tfsdk:"format"`
+	CityIds  types.Map    `tfsdk:"city_ids"`
+	HouseIds types.Map    `tfsdk:"house_ids"`
+	StoreIds types.Map    `tfsdk:"store_ids"`
+	InSync   types.Bool   `tfsdk:"in_sync"`
+}
+
+func (r *LayoutResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_layout"
+}
+
+func (r *LayoutResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Cities, houses and stores declared by a YAML or JSON document shaped like " +
+			"`example/config.yml`",
+		Version: 1,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Layout identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"document": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Layout document, listing cities with their nested houses and stores",
+			},
+			"format": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("yaml"),
+				MarkdownDescription: "Layout document format, either `yaml` or `json`",
+				Validators: []validator.String{
+					stringvalidator.OneOf("yaml", "json"),
+				},
+			},
+			"city_ids": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "City identifiers keyed by city name",
+			},
+			"house_ids": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "House identifiers keyed by `city/address`",
+			},
+			"store_ids": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "Store identifiers keyed by `city/address`",
+			},
+			"in_sync": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the API matches the document, false when objects were changed outside of Terraform",
+			},
+		},
+	}
+}
+
+func (r *LayoutResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: idsToInt64StateUpgrader([]string{"city_ids", "house_ids", "store_ids"}, nil),
+	}
+}
+
+func (r *LayoutResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
+				"Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.storeTypes = providerData.StoreTypes
+	r.nameAffixes = providerData.NameAffixes
+}
+
+func (r *LayoutResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data LayoutResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Document.IsUnknown() || data.Format.IsUnknown() {
+		return
+	}
+
+	format := "yaml"
+	if !data.Format.IsNull() {
+		format = data.Format.ValueString()
+	}
+	// Store types are checked during plan, against the store types accepted by
+	// the API.
+	if _, err := parseLayout(data.Document.ValueString(), format, nil); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("document"), "Invalid Layout Document", err.Error())
+	}
+}
+
+func (r *LayoutResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *LayoutResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to generate layout id, got error: %s", err))
+		return
+	}
+	data.Id = types.StringValue(id)
+
+	resp.Diagnostics.Append(r.apply(ctx, data, &LayoutResourceModel{})...)
+
+	tflog.Trace(ctx, "created a layout resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LayoutResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *LayoutResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The document was validated when applied, store types are not checked
+	// again so that the layout can be refreshed after the API drops one.
+	layout, err := parseLayout(data.Document.ValueString(), data.Format.ValueString(), nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Layout Document", err.Error())
+		return
+	}
+
+	cityIds, houseIds, storeIds, diags := layoutIds(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cities, houses, stores, err := listAll(r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to read layout, got error: %s", err))
+		return
+	}
+
+	inSync := true
+	for _, city := range layout.Cities {
+		existing, ok := cities[cityIds[city.Name]]
+		if !ok {
+			delete(cityIds, city.Name)
+			inSync = false
+		} else if r.nameAffixes.configName(existing.Name) != city.Name || !equalPointers(existing.Touristic, city.Touristic) {
+			inSync = false
+		}
+
+		for _, house := range city.Houses {
+			key := city.Name + "/" + house.Address
+			existing, ok := houses[houseIds[key]]
+			if !ok {
+				delete(houseIds, key)
+				inSync = false
+			} else if existing.CityId != cityIds[city.Name] || existing.Address != house.Address ||
+				!equalPointers(existing.Inhabitants, house.Inhabitants) {
+				inSync = false
+			}
+		}
+
+		for _, store := range city.Stores {
+			key := city.Name + "/" + store.Address
+			existing, ok := stores[storeIds[key]]
+			if !ok {
+				delete(storeIds, key)
+				inSync = false
+			} else if existing.CityId != cityIds[city.Name] || existing.Address != store.Address ||
+				r.nameAffixes.configName(existing.Name) != store.Name || !strings.EqualFold(existing.Type, store.Type) {
+				inSync = false
+			}
+		}
+	}
+
+	resp.Diagnostics.Append(setLayoutIds(ctx, data, cityIds, houseIds, storeIds)...)
+	data.InSync = types.BoolValue(inSync)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LayoutResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state *LayoutResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, data, state)...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LayoutResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *LayoutResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cityIds, houseIds, storeIds, diags := layoutIds(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.deleteObjects(ctx, "houses", houseIds, nil)...)
+	resp.Diagnostics.Append(r.deleteObjects(ctx, "stores", storeIds, nil)...)
+	if resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(setLayoutIds(ctx, data, cityIds, houseIds, storeIds)...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	resp.Diagnostics.Append(r.deleteObjects(ctx, "cities", cityIds, nil)...)
+	if resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(setLayoutIds(ctx, data, cityIds, houseIds, storeIds)...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	}
+}
+
+func (r *LayoutResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan *LayoutResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !plan.Document.IsUnknown() && !plan.Format.IsUnknown() {
+		if _, err := parseLayout(plan.Document.ValueString(), plan.Format.ValueString(), r.storeTypes.list(ctx)); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("document"), "Invalid Layout Document", err.Error())
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("in_sync"), types.BoolValue(true))...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var inSync types.Bool
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("in_sync"), &inSync)...)
+	if resp.Diagnostics.HasError() || inSync.ValueBool() {
+		return
+	}
+
+	// Objects changed outside of Terraform are reconciled on the next apply,
+	// which may create objects and change the identifiers.
+	for _, attribute := range []string{"city_ids", "house_ids", "store_ids"} {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attribute), types.MapUnknown(types.Int64Type))...)
+	}
+}
+
+// apply reconciles the API with the planned document, in dependency order:
+// cities first, then their houses and stores, and finally removes the objects
+// that are no longer part of the document, children first. Identifiers of the
+// objects successfully reconciled are saved even when some operations fail.
+func (r *LayoutResource) apply(ctx context.Context, data, prior *LayoutResourceModel) (diags diag.Diagnostics) {
+	layout, err := parseLayout(data.Document.ValueString(), data.Format.ValueString(), r.storeTypes.list(ctx))
+	if err != nil {
+		diags.AddError("Invalid Layout Document", err.Error())
+		return diags
+	}
+
+	priorCityIds, priorHouseIds, priorStoreIds, d := layoutIds(ctx, prior)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	cities, houses, stores, err := listAll(r.client)
+	if err != nil {
+		diags.AddError("Client Error",
+			fmt.Sprintf("Unable to read layout, got error: %s", err))
+		return diags
+	}
+
+	cityIds := make(map[string]int)
+	houseIds := make(map[string]int)
+	storeIds := make(map[string]int)
+	defer func() {
+		diags.Append(setLayoutIds(ctx, data, cityIds, houseIds, storeIds)...)
+		data.InSync = types.BoolValue(!diags.HasError())
+	}()
+
+	// Cities
+	cityResults := make([]int, len(layout.Cities))
+	errs := runConcurrently(len(layout.Cities), maxConcurrentRequests, func(i int) error {
+		city := layout.Cities[i]
+		body := &City{Name: r.nameAffixes.apiName(city.Name), Touristic: city.Touristic}
+		if existing, ok := cities[priorCityIds[city.Name]]; ok {
+			cityResults[i] = priorCityIds[city.Name]
+			if existing.Name == body.Name && equalPointers(existing.Touristic, city.Touristic) {
+				return nil
+			}
+			return retry(ctx, func() error {
+				return updateObject(r.client, fmt.Sprintf("cities/%d", cityResults[i]), body)
+			})
+		}
+		created := &City{}
+		if err := createObject(r.client, "cities", body, created); err != nil {
+			return err
+		}
+		cityResults[i] = created.Id
+		return nil
+	})
+	for i, city := range layout.Cities {
+		if cityResults[i] != 0 {
+			cityIds[city.Name] = cityResults[i]
+		}
+		if errs[i] == nil {
+			continue
+		}
+		diags.AddError("Client Error",
+			fmt.Sprintf("Unable to reconcile city %q, got error: %s", city.Name, errs[i]))
+
+		// Keep the children of a city that could not be reconciled
+		for _, house := range city.Houses {
+			if id, ok := priorHouseIds[city.Name+"/"+house.Address]; ok {
+				houseIds[city.Name+"/"+house.Address] = id
+			}
+		}
+		for _, store := range city.Stores {
+			if id, ok := priorStoreIds[city.Name+"/"+store.Address]; ok {
+				storeIds[city.Name+"/"+store.Address] = id
+			}
+		}
+	}
+
+	// Houses and stores of the reconciled cities
+	type child struct {
+		key   string
+		kind  string
+		house House
+		store Store
+	}
+	children := []child{}
+	for i, city := range layout.Cities {
+		cityId, ok := cityIds[city.Name]
+		if errs[i] != nil || !ok {
+			continue
+		}
+		for _, house := range city.Houses {
+			children = append(children, child{key: city.Name + "/" + house.Address, kind: "houses",
+				house: House{CityId: cityId, Address: house.Address, Inhabitants: house.Inhabitants}})
+		}
+		for _, store := range city.Stores {
+			children = append(children, child{key: city.Name + "/" + store.Address, kind: "stores",
+				store: Store{CityId: cityId, Address: store.Address, Name: r.nameAffixes.apiName(store.Name), Type: store.Type}})
+		}
+	}
+
+	childResults := make([]int, len(children))
+	errs = runConcurrently(len(children), maxConcurrentRequests, func(i int) error {
+		c := children[i]
+		if c.kind == "houses" {
+			if existing, ok := houses[priorHouseIds[c.key]]; ok {
+				childResults[i] = priorHouseIds[c.key]
+				if existing.CityId == c.house.CityId && existing.Address == c.house.Address &&
+					equalPointers(existing.Inhabitants, c.house.Inhabitants) {
+					return nil
+				}
+				return retry(ctx, func() error {
+					return updateObject(r.client, fmt.Sprintf("houses/%d", childResults[i]), &c.house)
+				})
+			}
+			created := &House{}
+			if err := createObject(r.client, "houses", &c.house, created); err != nil {
+				return err
+			}
+			childResults[i] = created.Id
+			return nil
+		}
+
+		if existing, ok := stores[priorStoreIds[c.key]]; ok {
+			childResults[i] = priorStoreIds[c.key]
+			if existing.CityId == c.store.CityId && existing.Address == c.store.Address &&
+				existing.Name == c.store.Name && existing.Type == c.store.Type {
+				return nil
+			}
+			return retry(ctx, func() error {
+				return updateObject(r.client, fmt.Sprintf("stores/%d", childResults[i]), &c.store)
+			})
+		}
+		created := &Store{}
+		if err := createObject(r.client, "stores", &c.store, created); err != nil {
+			return err
+		}
+		childResults[i] = created.Id
+		return nil
+	})
+	for i, c := range children {
+		if errs[i] != nil {
+			diags.AddError("Client Error",
+				fmt.Sprintf("Unable to reconcile %s %q, got error: %s", c.kind, c.key, errs[i]))
+		}
+		if childResults[i] == 0 {
+			continue
+		}
+		if c.kind == "houses" {
+			houseIds[c.key] = childResults[i]
+		} else {
+			storeIds[c.key] = childResults[i]
+		}
+	}
+
+	// Objects removed from the document, children before their city. Objects
+	// that cannot be deleted are kept in state.
+	removedHouseIds := make(map[string]int)
+	for key, id := range priorHouseIds {
+		if _, ok := houseIds[key]; !ok && id != 0 {
+			removedHouseIds[key] = id
+		}
+	}
+	removedStoreIds := make(map[string]int)
+	for key, id := range priorStoreIds {
+		if _, ok := storeIds[key]; !ok && id != 0 {
+			removedStoreIds[key] = id
+		}
+	}
+	diags.Append(r.deleteObjects(ctx, "houses", removedHouseIds, houseIds)...)
+	diags.Append(r.deleteObjects(ctx, "stores", removedStoreIds, storeIds)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	removedCityIds := make(map[string]int)
+	for name, id := range priorCityIds {
+		if _, ok := cityIds[name]; !ok && id != 0 {
+			removedCityIds[name] = id
+		}
+	}
+	diags.Append(r.deleteObjects(ctx, "cities", removedCityIds, cityIds)...)
+
+	return diags
+}
+
+// deleteObjects deletes the objects of the given kind concurrently. Deleted
+// objects are removed from ids, while the ones that failed are added to
+// remaining when set.
+func (r *LayoutResource) deleteObjects(ctx context.Context, kind string, ids, remaining map[string]int) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	keys := sortedKeys(ids)
+	errs := runConcurrently(len(keys), maxConcurrentRequests, func(i int) error {
+		return retry(ctx, func() error {
+			return deleteObject(r.client, fmt.Sprintf("%s/%d", kind, ids[keys[i]]))
+		})
+	})
+
+	for i, key := range keys {
+		if errs[i] != nil {
+			diags.AddError("Client Error",
+				fmt.Sprintf("Unable to delete %s %q, got error: %s", kind, key, errs[i]))
+			if remaining != nil {
+				remaining[key] = ids[key]
+			}
+			continue
+		}
+		delete(ids, key)
+	}
+
+	tflog.Debug(ctx, "deleted layout objects", map[string]any{"kind": kind, "count": len(keys)})
+	return diags
+}
+
+// layoutIds returns the identifier maps saved in the model.
+func layoutIds(ctx context.Context, data *LayoutResourceModel) (map[string]int, map[string]int, map[string]int, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	maps := []map[string]int{{}, {}, {}}
+	for i, value := range []types.Map{data.CityIds, data.HouseIds, data.StoreIds} {
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		diags.Append(value.ElementsAs(ctx, &maps[i], false)...)
+	}
+	return maps[0], maps[1], maps[2], diags
+}
+
+// setLayoutIds saves the identifier maps in the model.
+func setLayoutIds(ctx context.Context, data *LayoutResourceModel, cityIds, houseIds, storeIds map[string]int) diag.Diagnostics {
+	var diags, d diag.Diagnostics
+
+	data.CityIds, d = types.MapValueFrom(ctx, types.Int64Type, cityIds)
+	diags.Append(d...)
+	data.HouseIds, d = types.MapValueFrom(ctx, types.Int64Type, houseIds)
+	diags.Append(d...)
+	data.StoreIds, d = types.MapValueFrom(ctx, types.Int64Type, storeIds)
+	diags.Append(d...)
+	return diags
+}
+
+// listAll returns every city, house and store indexed by identifier.
+func listAll(c *client.SendoraCityClient) (map[int]City, map[int]House, map[int]Store, error) {
+	var cities []City
+	var houses []House
+	var stores []Store
+	errs := runConcurrently(3, maxConcurrentRequests, func(i int) (err error) {
+		switch i {
+		case 0:
+			cities, err = listCities(c, nil)
+		case 1:
+			houses, err = listHouses(c, nil)
+		default:
+			stores, err = listStores(c, nil)
+		}
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	citiesById := make(map[int]City)
+	for _, city := range cities {
+		citiesById[city.Id] = city
+	}
+	housesById := make(map[int]House)
+	for _, house := range houses {
+		housesById[house.Id] = house
+	}
+	storesById := make(map[int]Store)
+	for _, store := range stores {
+		storesById[store.Id] = store
+	}
+	return citiesById, housesById, storesById, nil
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLayoutResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Invalid documents are rejected at plan time
+			{
+				Config:      testAccLayoutResourceYamlConfig("layout-test-city", "unknown"),
+				ExpectError: regexp.MustCompile("Invalid Layout Document"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccLayoutResourceYamlConfig("layout-test-city", "food"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sendoracity_layout.test", "id"),
+					resource.TestCheckResourceAttr("sendoracity_layout.test", "in_sync", "true"),
+					resource.TestCheckResourceAttrSet("sendoracity_layout.test", "city_ids.layout-test-city"),
+					resource.TestCheckResourceAttrSet("sendoracity_layout.test", "house_ids.layout-test-city/5 avenue Anatole France"),
+					resource.TestCheckResourceAttrSet("sendoracity_layout.test", "store_ids.layout-test-city/6 avenue Anatole France"),
+				),
+			},
+			// The identifiers are numbers, usable as the city_id of a house
+			{
+				Config: testAccLayoutResourceYamlConfig("layout-test-city", "food") + `
+resource "sendoracity_house" "test" {
+  city_id     = sendoracity_layout.test.city_ids["layout-test-city"]
+  address     = "7 avenue Anatole France"
+  inhabitants = 2
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("sendoracity_house.test", "city_id", "sendoracity_layout.test", "city_ids.layout-test-city"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: testAccLayoutResourceJsonConfig("layout-test-city"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_layout.test", "format", "json"),
+					resource.TestCheckResourceAttr("sendoracity_layout.test", "house_ids.%", "1"),
+					resource.TestCheckResourceAttr("sendoracity_layout.test", "store_ids.%", "0"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccLayoutResourceNameAffixes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, the API names carry the affixes while the
+			// data sources strip them
+			{
+				Config: testAccLayoutResourceNameAffixesConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_layout.test", "in_sync", "true"),
+					resource.TestCheckResourceAttrSet("sendoracity_layout.test", "city_ids.layout-test-name-affixes"),
+					resource.TestCheckResourceAttr("data.sendoracity_city.test", "name", "layout-test-name-affixes"),
+					resource.TestCheckResourceAttr("data.sendoracity_city.test", "stores.0.name", "Carrfour"),
+					resource.TestCheckResourceAttrPair("data.sendoracity_city.raw", "id", "data.sendoracity_city.test", "id"),
+					resource.TestCheckResourceAttr("data.sendoracity_city.raw", "stores.0.name", "test-Carrfour-ephemeral"),
+				),
+			},
+			// The refreshed layout stays in sync
+			{
+				Config:   testAccLayoutResourceNameAffixesConfig(),
+				PlanOnly: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccLayoutResourceNameAffixesConfig() string {
+	return `
+provider "sendoracity" {
+  name_prefix = "test-"
+  name_suffix = "-ephemeral"
+}
+
+provider "sendoracity" {
+  alias = "raw"
+}
+` + testAccLayoutResourceYamlConfig("layout-test-name-affixes", "food") + `
+data "sendoracity_city" "test" {
+  name           = "layout-test-name-affixes"
+  include_stores = true
+
+  depends_on = [sendoracity_layout.test]
+}
+
+# Without affixes, the data source finds the city by its name in the API
+data "sendoracity_city" "raw" {
+  provider = sendoracity.raw
+
+  name           = "test-layout-test-name-affixes-ephemeral"
+  include_stores = true
+
+  depends_on = [sendoracity_layout.test]
+}
+`
+}
+
+func testAccLayoutResourceYamlConfig(cityName, storeType string) string {
+	return fmt.Sprintf(`
+resource "sendoracity_layout" "test" {
+  document = <<-EOT
+    cities:
+      - name: %s
+        touristic: true
+        houses:
+          - address: 5 avenue Anatole France
+            inhabitants: 100
+        stores:
+          - name: Carrfour
+            address: 6 avenue Anatole France
+            type: %s
+  EOT
+}
+`, cityName, storeType)
+}
+
+func testAccLayoutResourceJsonConfig(cityName string) string {
+	return fmt.Sprintf(`
+resource "sendoracity_layout" "test" {
+  format = "json"
+  document = jsonencode({
+    cities = [{
+      name      = "%s"
+      touristic = false
+      houses = [{
+        address     = "5 avenue Anatole France"
+        inhabitants = 50
+      }]
+    }]
+  })
+}
+`, cityName)
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLayout(t *testing.T) {
+	yamlDocument := `
+cities:
+  - name: Troyes
+    touristic: true
+    houses:
+      - address: 5 avenue Anatole France
+        inhabitants: 0
+    stores:
+      - name: Carrfour
+        address: 6 avenue Anatole France
+        type: food
+`
+	want := &layoutDocument{Cities: []layoutCity{{
+		Name:      "Troyes",
+		Touristic: pointer(true),
+		Houses:    []layoutHouse{{Address: "5 avenue Anatole France", Inhabitants: pointer(0)}},
+		Stores:    []layoutStore{{Name: "Carrfour", Address: "6 avenue Anatole France", Type: "Food"}},
+	}}}
+
+	tests := []struct {
+		name       string
+		document   string
+		format     string
+		storeTypes []string
+		want       *layoutDocument
+		wantErr    string
+	}{
+		{
+			name:       "yaml",
+			document:   yamlDocument,
+			format:     "yaml",
+			storeTypes: builtinStoreTypes,
+			want:       want,
+		},
+		{
+			name: "json",
+			document: `{"cities": [{"name": "Troyes", "touristic": true,
+				"houses": [{"address": "5 avenue Anatole France", "inhabitants": 0}],
+				"stores": [{"name": "Carrfour", "address": "6 avenue Anatole France", "type": "FOOD"}]}]}`,
+			format:     "json",
+			storeTypes: builtinStoreTypes,
+			want:       want,
+		},
+		{
+			name:     "store types not checked",
+			document: "cities:\n  - name: Troyes\n    touristic: false\n    stores:\n      - {name: Toys, address: 1 rue Thiers, type: toys}\n",
+			format:   "yaml",
+			want: &layoutDocument{Cities: []layoutCity{{
+				Name:      "Troyes",
+				Touristic: pointer(false),
+				Stores:    []layoutStore{{Name: "Toys", Address: "1 rue Thiers", Type: "toys"}},
+			}}},
+		},
+		{
+			name:       "store type published by the API",
+			document:   "cities:\n  - name: Troyes\n    touristic: false\n    stores:\n      - {name: Toys, address: 1 rue Thiers, type: toys}\n",
+			format:     "yaml",
+			storeTypes: []string{"Food", "Toys"},
+			want: &layoutDocument{Cities: []layoutCity{{
+				Name:      "Troyes",
+				Touristic: pointer(false),
+				Stores:    []layoutStore{{Name: "Toys", Address: "1 rue Thiers", Type: "Toys"}},
+			}}},
+		},
+		{
+			name:       "unknown store type",
+			document:   "cities:\n  - name: Troyes\n    touristic: false\n    stores:\n      - {name: Toys, address: 1 rue Thiers, type: toys}\n",
+			format:     "yaml",
+			storeTypes: builtinStoreTypes,
+			wantErr:    `city "Troyes": store "Toys": type`,
+		},
+		{
+			name:     "unknown yaml field",
+			document: "cities:\n  - name: Troyes\n    touristic: false\n    mayor: Someone\n",
+			format:   "yaml",
+			wantErr:  "invalid YAML document",
+		},
+		{
+			name:     "unknown json field",
+			document: `{"cities": [{"name": "Troyes", "touristic": false, "mayor": "Someone"}]}`,
+			format:   "json",
+			wantErr:  "invalid JSON document",
+		},
+		{
+			name:     "unsupported format",
+			document: "cities: []",
+			format:   "toml",
+			wantErr:  `unsupported format "toml"`,
+		},
+		{
+			name:     "missing city name",
+			document: "cities:\n  - touristic: false\n",
+			format:   "yaml",
+			wantErr:  "cities[0]: name is required",
+		},
+		{
+			name:     "duplicate city",
+			document: "cities:\n  - {name: Troyes, touristic: false}\n  - {name: Troyes, touristic: true}\n",
+			format:   "yaml",
+			wantErr:  `cities[1]: duplicate city name "Troyes"`,
+		},
+		{
+			name:     "missing touristic",
+			document: "cities:\n  - name: Troyes\n",
+			format:   "yaml",
+			wantErr:  `city "Troyes": touristic is required`,
+		},
+		{
+			name:     "duplicate house address",
+			document: "cities:\n  - name: Troyes\n    touristic: false\n    houses:\n      - {address: 1 rue Thiers, inhabitants: 1}\n      - {address: 1 rue Thiers, inhabitants: 2}\n",
+			format:   "yaml",
+			wantErr:  `city "Troyes": duplicate house address "1 rue Thiers"`,
+		},
+		{
+			name:     "missing inhabitants",
+			document: "cities:\n  - name: Troyes\n    touristic: false\n    houses:\n      - {address: 1 rue Thiers}\n",
+			format:   "yaml",
+			wantErr:  `house "1 rue Thiers": inhabitants is required`,
+		},
+		{
+			name:     "negative inhabitants",
+			document: "cities:\n  - name: Troyes\n    touristic: false\n    houses:\n      - {address: 1 rue Thiers, inhabitants: -1}\n",
+			format:   "yaml",
+			wantErr:  `house "1 rue Thiers": inhabitants must be zero or more`,
+		},
+		{
+			name:     "duplicate store address",
+			document: "cities:\n  - name: Troyes\n    touristic: false\n    stores:\n      - {name: A, address: 1 rue Thiers, type: Food}\n      - {name: B, address: 1 rue Thiers, type: Food}\n",
+			format:   "yaml",
+			wantErr:  `city "Troyes": duplicate store address "1 rue Thiers"`,
+		},
+		{
+			name:     "missing store name",
+			document: "cities:\n  - name: Troyes\n    touristic: false\n    stores:\n      - {address: 1 rue Thiers, type: Food}\n",
+			format:   "yaml",
+			wantErr:  `city "Troyes": stores[0]: name and address are required`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseLayout(test.document, test.format, test.storeTypes)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("got error %v, want error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
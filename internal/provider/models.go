@@ -1,22 +1,166 @@
 package provider
 
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// builtinStoreTypes lists the store types accepted by the API when it does not
+// publish them.
+var builtinStoreTypes = []string{"Food", "Sports", "Clothes", "Electronics", "Other"}
+
+// API models. Fields for which the zero value is meaningful are pointers, so
+// that a value omitted by the API or left unset in Terraform is told apart
+// from zero. Identifiers, names and addresses cannot be zero or empty.
+
 type City struct {
 	Id        int    `json:"id,omitempty"`
 	Name      string `json:"name,omitempty"`
 	Touristic *bool  `json:"touristic,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
 }
 
 type House struct {
 	Id          int    `json:"id,omitempty"`
 	CityId      int    `json:"cityid,omitempty"`
 	Address     string `json:"address,omitempty"`
-	Inhabitants int    `json:"inhabitants,omitempty"`
+	Inhabitants *int   `json:"inhabitants,omitempty"`
+	Timestamp   string `json:"timestamp,omitempty"`
 }
 
 type Store struct {
-	Id      int    `json:"id,omitempty"`
-	CityId  int    `json:"cityid,omitempty"`
-	Address string `json:"address,omitempty"`
-	Name    string `json:"name,omitempty"`
-	Type    string `json:"type,omitempty"`
+	Id        int    `json:"id,omitempty"`
+	CityId    int    `json:"cityid,omitempty"`
+	Address   string `json:"address,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// cityBody returns the API payload for a city resource. Null and unknown
+// values are left unset.
+func cityBody(data *CityResourceModel) *City {
+	return &City{
+		Name:      data.Name.ValueString(),
+		Touristic: boolPointer(data.Touristic),
+	}
+}
+
+// refreshCityModel updates a city resource model with the values returned by
+// the API. Values omitted by the API become null.
+func refreshCityModel(data *CityResourceModel, city *City) {
+	data.Name = stringValue(city.Name)
+	data.Touristic = boolValue(city.Touristic)
+	data.CreatedAt = createdAtValue(city.Timestamp)
+}
+
+// houseBody returns the API payload for a house resource. Null and unknown
+// values are left unset.
+func houseBody(data *HouseResourceModel) *House {
+	return &House{
+		CityId:      int(data.CityId.ValueInt64()),
+		Address:     data.Address.ValueString(),
+		Inhabitants: intPointer(data.Inhabitants),
+	}
+}
+
+// refreshHouseModel updates a house resource model with the values returned
+// by the API. Values omitted by the API become null.
+func refreshHouseModel(data *HouseResourceModel, house *House) {
+	data.CityId = idValue(house.CityId)
+	data.Address = addressValue(house.Address)
+	data.Inhabitants = int64Value(house.Inhabitants)
+	data.CreatedAt = createdAtValue(house.Timestamp)
+}
+
+// storeBody returns the API payload for a store resource, with the store type
+// in its canonical form. Null and unknown values are left unset.
+func storeBody(data *StoreResourceModel, storeTypes []string) *Store {
+	return &Store{
+		CityId:  int(data.CityId.ValueInt64()),
+		Address: data.Address.ValueString(),
+		Name:    data.Name.ValueString(),
+		Type:    storeTypeBody(data.Type.ValueString(), storeTypes),
+	}
+}
+
+// refreshStoreModel updates a store resource model with the values returned
+// by the API. Values omitted by the API become null.
+func refreshStoreModel(data *StoreResourceModel, store *Store) {
+	data.CityId = idValue(store.CityId)
+	data.Address = addressValue(store.Address)
+	data.Name = stringValue(store.Name)
+	data.Type = storeTypeValue(store.Type)
+	data.CreatedAt = createdAtValue(store.Timestamp)
+}
+
+// boolPointer converts a Terraform value to an API value, nil when null or
+// unknown.
+func boolPointer(value types.Bool) *bool {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	return pointer(value.ValueBool())
+}
+
+// intPointer converts a Terraform value to an API value, nil when null or
+// unknown.
+func intPointer(value types.Int64) *int {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	return pointer(int(value.ValueInt64()))
+}
+
+// boolValue converts an API value to a Terraform value, null when omitted.
+func boolValue(value *bool) types.Bool {
+	if value == nil {
+		return types.BoolNull()
+	}
+	return types.BoolValue(*value)
+}
+
+// int64Value converts an API value to a Terraform value, null when omitted.
+func int64Value(value *int) types.Int64 {
+	if value == nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(*value))
+}
+
+// stringValue converts an API value to a Terraform value, null when omitted.
+func stringValue(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// idValue converts an API identifier to a Terraform value, null when omitted.
+func idValue(value int) types.Int64 {
+	if value == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(value))
+}
+
+// pointer returns a pointer to a copy of value.
+func pointer[T any](value T) *T {
+	return &value
+}
+
+// valueOf returns the value pointed to, or the zero value when nil.
+func valueOf[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+	return *value
+}
+
+// equalPointers reports whether both pointers are nil or point to equal values.
+func equalPointers[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
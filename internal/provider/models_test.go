@@ -0,0 +1,391 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCityBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		model CityResourceModel
+		want  string
+	}{
+		{
+			name:  "all fields",
+			model: CityResourceModel{Name: types.StringValue("Paris"), Touristic: types.BoolValue(true)},
+			want:  `{"name":"Paris","touristic":true}`,
+		},
+		{
+			name:  "touristic false",
+			model: CityResourceModel{Name: types.StringValue("Troyes"), Touristic: types.BoolValue(false)},
+			want:  `{"name":"Troyes","touristic":false}`,
+		},
+		{
+			name:  "touristic null",
+			model: CityResourceModel{Name: types.StringValue("Troyes"), Touristic: types.BoolNull()},
+			want:  `{"name":"Troyes"}`,
+		},
+		{
+			name:  "touristic unknown",
+			model: CityResourceModel{Name: types.StringValue("Troyes"), Touristic: types.BoolUnknown()},
+			want:  `{"name":"Troyes"}`,
+		},
+		{
+			name:  "name null",
+			model: CityResourceModel{Name: types.StringNull(), Touristic: types.BoolValue(true)},
+			want:  `{"touristic":true}`,
+		},
+		{
+			name:  "name unknown",
+			model: CityResourceModel{Name: types.StringUnknown(), Touristic: types.BoolValue(true)},
+			want:  `{"touristic":true}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assertJSON(t, cityBody(&test.model), test.want)
+		})
+	}
+}
+
+func TestRefreshCityModel(t *testing.T) {
+	tests := []struct {
+		name string
+		city City
+		want CityResourceModel
+	}{
+		{
+			name: "all fields",
+			city: City{Id: 1, Name: "Paris", Touristic: pointer(true), Timestamp: "2023-05-01T10:00:00Z"},
+			want: CityResourceModel{
+				Name:      types.StringValue("Paris"),
+				Touristic: types.BoolValue(true),
+				CreatedAt: types.StringValue("2023-05-01T10:00:00Z"),
+			},
+		},
+		{
+			name: "touristic false",
+			city: City{Id: 1, Name: "Troyes", Touristic: pointer(false)},
+			want: CityResourceModel{
+				Name:      types.StringValue("Troyes"),
+				Touristic: types.BoolValue(false),
+				CreatedAt: types.StringNull(),
+			},
+		},
+		{
+			name: "omitted fields",
+			city: City{Id: 1},
+			want: CityResourceModel{
+				Name:      types.StringNull(),
+				Touristic: types.BoolNull(),
+				CreatedAt: types.StringNull(),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := CityResourceModel{}
+			refreshCityModel(&got, &test.city)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHouseBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		model HouseResourceModel
+		want  string
+	}{
+		{
+			name: "all fields",
+			model: HouseResourceModel{
+				CityId:      types.Int64Value(1),
+				Address:     AddressValue{StringValue: types.StringValue("5 avenue Anatole France")},
+				Inhabitants: types.Int64Value(4),
+			},
+			want: `{"cityid":1,"address":"5 avenue Anatole France","inhabitants":4}`,
+		},
+		{
+			name: "inhabitants zero",
+			model: HouseResourceModel{
+				CityId:      types.Int64Value(1),
+				Address:     AddressValue{StringValue: types.StringValue("5 avenue Anatole France")},
+				Inhabitants: types.Int64Value(0),
+			},
+			want: `{"cityid":1,"address":"5 avenue Anatole France","inhabitants":0}`,
+		},
+		{
+			name: "inhabitants null",
+			model: HouseResourceModel{
+				CityId:      types.Int64Value(1),
+				Address:     AddressValue{StringValue: types.StringValue("5 avenue Anatole France")},
+				Inhabitants: types.Int64Null(),
+			},
+			want: `{"cityid":1,"address":"5 avenue Anatole France"}`,
+		},
+		{
+			name: "inhabitants unknown",
+			model: HouseResourceModel{
+				CityId:      types.Int64Value(1),
+				Address:     AddressValue{StringValue: types.StringValue("5 avenue Anatole France")},
+				Inhabitants: types.Int64Unknown(),
+			},
+			want: `{"cityid":1,"address":"5 avenue Anatole France"}`,
+		},
+		{
+			name: "city and address unknown",
+			model: HouseResourceModel{
+				CityId:      types.Int64Unknown(),
+				Address:     AddressValue{StringValue: types.StringUnknown()},
+				Inhabitants: types.Int64Value(2),
+			},
+			want: `{"inhabitants":2}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assertJSON(t, houseBody(&test.model), test.want)
+		})
+	}
+}
+
+func TestRefreshHouseModel(t *testing.T) {
+	tests := []struct {
+		name  string
+		house House
+		want  HouseResourceModel
+	}{
+		{
+			name:  "all fields",
+			house: House{Id: 2, CityId: 1, Address: "5 avenue Anatole France", Inhabitants: pointer(4), Timestamp: "2023-05-01T10:00:00Z"},
+			want: HouseResourceModel{
+				CityId:      types.Int64Value(1),
+				Address:     AddressValue{StringValue: types.StringValue("5 avenue Anatole France")},
+				Inhabitants: types.Int64Value(4),
+				CreatedAt:   types.StringValue("2023-05-01T10:00:00Z"),
+			},
+		},
+		{
+			name:  "inhabitants zero",
+			house: House{Id: 2, CityId: 1, Address: "5 avenue Anatole France", Inhabitants: pointer(0)},
+			want: HouseResourceModel{
+				CityId:      types.Int64Value(1),
+				Address:     AddressValue{StringValue: types.StringValue("5 avenue Anatole France")},
+				Inhabitants: types.Int64Value(0),
+				CreatedAt:   types.StringNull(),
+			},
+		},
+		{
+			name:  "omitted fields",
+			house: House{Id: 2},
+			want: HouseResourceModel{
+				CityId:      types.Int64Null(),
+				Address:     AddressValue{StringValue: types.StringNull()},
+				Inhabitants: types.Int64Null(),
+				CreatedAt:   types.StringNull(),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := HouseResourceModel{}
+			refreshHouseModel(&got, &test.house)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStoreBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		model StoreResourceModel
+		want  string
+	}{
+		{
+			name: "all fields",
+			model: StoreResourceModel{
+				CityId:  types.Int64Value(1),
+				Address: AddressValue{StringValue: types.StringValue("1 rue de la Paix")},
+				Name:    types.StringValue("Cocci Marche"),
+				Type:    StoreTypeValue{StringValue: types.StringValue("Food")},
+			},
+			want: `{"cityid":1,"address":"1 rue de la Paix","name":"Cocci Marche","type":"Food"}`,
+		},
+		{
+			name: "type in lowercase",
+			model: StoreResourceModel{
+				CityId:  types.Int64Value(1),
+				Address: AddressValue{StringValue: types.StringValue("1 rue de la Paix")},
+				Name:    types.StringValue("Cocci Marche"),
+				Type:    StoreTypeValue{StringValue: types.StringValue("food")},
+			},
+			want: `{"cityid":1,"address":"1 rue de la Paix","name":"Cocci Marche","type":"Food"}`,
+		},
+		{
+			name: "null fields",
+			model: StoreResourceModel{
+				CityId:  types.Int64Null(),
+				Address: AddressValue{StringValue: types.StringNull()},
+				Name:    types.StringNull(),
+				Type:    StoreTypeValue{StringValue: types.StringNull()},
+			},
+			want: `{}`,
+		},
+		{
+			name: "unknown fields",
+			model: StoreResourceModel{
+				CityId:  types.Int64Unknown(),
+				Address: AddressValue{StringValue: types.StringUnknown()},
+				Name:    types.StringValue("Cocci Marche"),
+				Type:    StoreTypeValue{StringValue: types.StringUnknown()},
+			},
+			want: `{"name":"Cocci Marche"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assertJSON(t, storeBody(&test.model, builtinStoreTypes), test.want)
+		})
+	}
+}
+
+func TestRefreshStoreModel(t *testing.T) {
+	tests := []struct {
+		name  string
+		store Store
+		want  StoreResourceModel
+	}{
+		{
+			name:  "all fields",
+			store: Store{Id: 3, CityId: 1, Address: "1 rue de la Paix", Name: "Cocci Marche", Type: "Food", Timestamp: "2023-05-01T10:00:00Z"},
+			want: StoreResourceModel{
+				CityId:    types.Int64Value(1),
+				Address:   AddressValue{StringValue: types.StringValue("1 rue de la Paix")},
+				Name:      types.StringValue("Cocci Marche"),
+				Type:      StoreTypeValue{StringValue: types.StringValue("Food")},
+				CreatedAt: types.StringValue("2023-05-01T10:00:00Z"),
+			},
+		},
+		{
+			name:  "omitted fields",
+			store: Store{Id: 3},
+			want: StoreResourceModel{
+				CityId:    types.Int64Null(),
+				Address:   AddressValue{StringValue: types.StringNull()},
+				Name:      types.StringNull(),
+				Type:      StoreTypeValue{StringValue: types.StringNull()},
+				CreatedAt: types.StringNull(),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := StoreResourceModel{}
+			refreshStoreModel(&got, &test.store)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeModels(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		got  any
+		want any
+	}{
+		{
+			name: "city without touristic",
+			body: `{"id":1,"name":"Paris"}`,
+			got:  &City{},
+			want: &City{Id: 1, Name: "Paris"},
+		},
+		{
+			name: "city not touristic",
+			body: `{"id":1,"name":"Paris","touristic":false}`,
+			got:  &City{},
+			want: &City{Id: 1, Name: "Paris", Touristic: pointer(false)},
+		},
+		{
+			name: "house without inhabitants",
+			body: `{"id":2,"cityid":1,"address":"5 avenue Anatole France"}`,
+			got:  &House{},
+			want: &House{Id: 2, CityId: 1, Address: "5 avenue Anatole France"},
+		},
+		{
+			name: "house without inhabitants set to null",
+			body: `{"id":2,"cityid":1,"address":"5 avenue Anatole France","inhabitants":null}`,
+			got:  &House{},
+			want: &House{Id: 2, CityId: 1, Address: "5 avenue Anatole France"},
+		},
+		{
+			name: "empty house",
+			body: `{"id":2,"cityid":1,"address":"5 avenue Anatole France","inhabitants":0}`,
+			got:  &House{},
+			want: &House{Id: 2, CityId: 1, Address: "5 avenue Anatole France", Inhabitants: pointer(0)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(test.body), test.got); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(test.got, test.want) {
+				t.Errorf("got %+v, want %+v", test.got, test.want)
+			}
+		})
+	}
+}
+
+func TestEqualPointers(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *int
+		want bool
+	}{
+		{name: "both nil", want: true},
+		{name: "nil and zero", a: nil, b: pointer(0), want: false},
+		{name: "zero and nil", a: pointer(0), b: nil, want: false},
+		{name: "equal values", a: pointer(3), b: pointer(3), want: true},
+		{name: "different values", a: pointer(3), b: pointer(4), want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := equalPointers(test.a, test.b); got != test.want {
+				t.Errorf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+// assertJSON checks that value serializes to the expected JSON document.
+func assertJSON(t *testing.T, value any, want string) {
+	t.Helper()
+
+	got, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// nameAffixes holds the provider name_prefix and name_suffix, added to the
+// names of cities and stores sent to the API and removed from the names read
+// back, so that the state keeps the configured names.
+type nameAffixes struct {
+	Prefix string
+	Suffix string
+}
+
+// apiName returns the name of a city or store in the API.
+func (a nameAffixes) apiName(name string) string {
+	return a.Prefix + name + a.Suffix
+}
+
+// configName returns the configured name of a city or store named name in the
+// API. Names missing the prefix or suffix, such as those of objects created
+// outside of Terraform, are returned unchanged.
+func (a nameAffixes) configName(name string) string {
+	if len(name) < len(a.Prefix)+len(a.Suffix) || !strings.HasPrefix(name, a.Prefix) || !strings.HasSuffix(name, a.Suffix) {
+		return name
+	}
+	return name[len(a.Prefix) : len(name)-len(a.Suffix)]
+}
+
+// configNameValue returns the configured name of a name value read from the
+// API, null or unknown along with it.
+func (a nameAffixes) configNameValue(name types.String) types.String {
+	if name.IsNull() || name.IsUnknown() {
+		return name
+	}
+	return types.StringValue(a.configName(name.ValueString()))
+}
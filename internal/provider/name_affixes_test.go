@@ -0,0 +1,32 @@
+package provider
+
+import "testing"
+
+func TestNameAffixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		affixes  nameAffixes
+		config   string
+		apiName  string
+		readName string
+		want     string
+	}{
+		{name: "none", config: "Paris", apiName: "Paris", readName: "Paris", want: "Paris"},
+		{name: "prefix", affixes: nameAffixes{Prefix: "test-"}, config: "Paris", apiName: "test-Paris", readName: "test-Paris", want: "Paris"},
+		{name: "suffix", affixes: nameAffixes{Suffix: "-42"}, config: "Paris", apiName: "Paris-42", readName: "Paris-42", want: "Paris"},
+		{name: "both", affixes: nameAffixes{Prefix: "a-", Suffix: "-z"}, config: "Paris", apiName: "a-Paris-z", readName: "a-Paris-z", want: "Paris"},
+		{name: "created outside", affixes: nameAffixes{Prefix: "test-"}, config: "Lyon", apiName: "test-Lyon", readName: "Lyon", want: "Lyon"},
+		{name: "overlapping affixes", affixes: nameAffixes{Prefix: "ab", Suffix: "ba"}, config: "", apiName: "abba", readName: "aba", want: "aba"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.affixes.apiName(test.config); got != test.apiName {
+				t.Errorf("got API name %q, want %q", got, test.apiName)
+			}
+			if got := test.affixes.configName(test.readName); got != test.want {
+				t.Errorf("got config name %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &ParseAddressDataSource{}
+
+func NewParseAddressDataSource() datasource.DataSource {
+	return &ParseAddressDataSource{}
+}
+
+type ParseAddressDataSource struct {
+	addressNormalization addressNormalization
+}
+
+type ParseAddressDataSourceModel struct {
+	Address           types.String `tfsdk:"address"`
+	NormalizedAddress types.String `tfsdk:"normalized_address"`
+	StreetNumber      types.String `tfsdk:"street_number"`
+	StreetType        types.String `tfsdk:"street_type"`
+	StreetName        types.String `tfsdk:"street_name"`
+	PostalCode        types.String `tfsdk:"postal_code"`
+}
+
+func (d *ParseAddressDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_parse_address"
+}
+
+func (d *ParseAddressDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Parse address data source, splitting a French address into its components without calling the API",
+
+		Attributes: map[string]schema.Attribute{
+			"address": schema.StringAttribute{
+				MarkdownDescription: "Address to parse",
+				Required:            true,
+			},
+			"normalized_address": schema.StringAttribute{
+				MarkdownDescription: "Address in its canonical form, following the provider `address_normalization`",
+				Computed:            true,
+			},
+			"street_number": schema.StringAttribute{
+				MarkdownDescription: "Street number, with its repetition index such as `bis`",
+				Computed:            true,
+			},
+			"street_type": schema.StringAttribute{
+				MarkdownDescription: "Street type in its full form, lowercase and without accents, such as `avenue`",
+				Computed:            true,
+			},
+			"street_name": schema.StringAttribute{
+				MarkdownDescription: "Street name",
+				Computed:            true,
+			},
+			"postal_code": schema.StringAttribute{
+				MarkdownDescription: "Postal code, when present",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ParseAddressDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
+				"Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.addressNormalization = providerData.AddressNormalization
+}
+
+func (d *ParseAddressDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ParseAddressDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.NormalizedAddress = d.addressNormalization.normalizedAddressValue(data.Address)
+	data.StreetNumber, data.StreetType, data.StreetName, data.PostalCode = addressComponentValues(data.Address)
+
+	tflog.Trace(ctx, "read parse address from a data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccParseAddressDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccParseAddressDataSourceConfig("7 bis av. des Champs-Élysées, 75008 Paris"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sendoracity_parse_address.test", "normalized_address", "7 bis avenue des champs elysees 75008 paris"),
+					resource.TestCheckResourceAttr("data.sendoracity_parse_address.test", "street_number", "7 bis"),
+					resource.TestCheckResourceAttr("data.sendoracity_parse_address.test", "street_type", "avenue"),
+					resource.TestCheckResourceAttr("data.sendoracity_parse_address.test", "street_name", "des Champs-Élysées"),
+					resource.TestCheckResourceAttr("data.sendoracity_parse_address.test", "postal_code", "75008"),
+				),
+			},
+			// Read testing without postal code
+			{
+				Config: testAccParseAddressDataSourceConfig("10 rue de la Paix"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sendoracity_parse_address.test", "street_number", "10"),
+					resource.TestCheckResourceAttr("data.sendoracity_parse_address.test", "street_type", "rue"),
+					resource.TestCheckResourceAttr("data.sendoracity_parse_address.test", "street_name", "de la Paix"),
+					resource.TestCheckNoResourceAttr("data.sendoracity_parse_address.test", "postal_code"),
+				),
+			},
+		},
+	})
+}
+
+func testAccParseAddressDataSourceConfig(address string) string {
+	return fmt.Sprintf(`
+data "sendoracity_parse_address" "test" {
+  address = %q
+}
+`, address)
+}
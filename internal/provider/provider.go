@@ -4,11 +4,15 @@ import (
 	"context"
 	"os"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	client "github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
 )
 
@@ -16,10 +20,43 @@ var _ provider.Provider = &SendoraCityProvider{}
 
 type SendoraCityProvider struct {
 	version string
+	// addressNormalization is shared with the address type of the house and
+	// store schemas, built before the provider is configured.
+	addressNormalization *addressNormalization
 }
 
 type SendoraCityProviderModel struct {
-	BaseUri types.String `tfsdk:"base_uri"`
+	BaseUri                types.String `tfsdk:"base_uri"`
+	RelocationMode         types.String `tfsdk:"relocation_mode"`
+	MaxInhabitants         types.Int64  `tfsdk:"max_inhabitants"`
+	AddressNormalization   types.Object `tfsdk:"address_normalization"`
+	EnforceUniqueAddresses types.Bool   `tfsdk:"enforce_unique_addresses"`
+	EnforceUniqueCityNames types.Bool   `tfsdk:"enforce_unique_city_names"`
+	DefaultCityId          types.Int64  `tfsdk:"default_city_id"`
+	NamePrefix             types.String `tfsdk:"name_prefix"`
+	NameSuffix             types.String `tfsdk:"name_suffix"`
+}
+
+type SendoraCityProviderAddressNormalizationModel struct {
+	CollapseWhitespace  types.Bool `tfsdk:"collapse_whitespace"`
+	FoldCase            types.Bool `tfsdk:"fold_case"`
+	FoldAccents         types.Bool `tfsdk:"fold_accents"`
+	ExpandAbbreviations types.Bool `tfsdk:"expand_abbreviations"`
+}
+
+// SendoraCityProviderData is handed over to resources and data sources when
+// they are configured. DefaultCityId is kept as a value, as it is unknown
+// during plan when it depends on other resources.
+type SendoraCityProviderData struct {
+	Client                 *client.SendoraCityClient
+	RelocationMode         string
+	MaxInhabitants         int64
+	StoreTypes             *storeTypeCatalog
+	AddressNormalization   addressNormalization
+	EnforceUniqueAddresses bool
+	EnforceUniqueCityNames bool
+	DefaultCityId          types.Int64
+	NameAffixes            nameAffixes
 }
 
 func (p *SendoraCityProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -34,6 +71,71 @@ func (p *SendoraCityProvider) Schema(ctx context.Context, req provider.SchemaReq
 				MarkdownDescription: "City API base URI",
 				Optional:            true,
 			},
+			"relocation_mode": schema.StringAttribute{
+				MarkdownDescription: "Default behavior when the `city_id` of a house or store changes: " +
+					"`in_place` (default) updates the object, `replace` creates a new one",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(relocationModes...),
+				},
+			},
+			"max_inhabitants": schema.Int64Attribute{
+				MarkdownDescription: "Maximum inhabitants count of a house, checked during plan. Unlimited when not set",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"address_normalization": schema.SingleNestedAttribute{
+				MarkdownDescription: "Transformations applied to house and store addresses before comparing them, " +
+					"so that addresses differing only by these transformations are not reported as changes. All enabled by default",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"collapse_whitespace": schema.BoolAttribute{
+						MarkdownDescription: "Ignore surrounding whitespace, and treat runs of whitespace, commas and hyphens as a single space. Defaults to `true`",
+						Optional:            true,
+					},
+					"fold_case": schema.BoolAttribute{
+						MarkdownDescription: "Ignore case. Defaults to `true`",
+						Optional:            true,
+					},
+					"fold_accents": schema.BoolAttribute{
+						MarkdownDescription: "Ignore accents. Defaults to `true`",
+						Optional:            true,
+					},
+					"expand_abbreviations": schema.BoolAttribute{
+						MarkdownDescription: "Expand abbreviated French street types, such as `av.` for `avenue` or `bd` for `boulevard`. Defaults to `true`",
+						Optional:            true,
+					},
+				},
+			},
+			"enforce_unique_addresses": schema.BoolAttribute{
+				MarkdownDescription: "Fail the plan when a house or store is created at, or moved to, an address already used " +
+					"by another house or store of the same city. Defaults to `false`",
+				Optional: true,
+			},
+			"enforce_unique_city_names": schema.BoolAttribute{
+				MarkdownDescription: "Fail the plan when a city is created or renamed with the name of another city, " +
+					"regardless of case. Defaults to `false`",
+				Optional: true,
+			},
+			"default_city_id": schema.Int64Attribute{
+				MarkdownDescription: "City identifier of the houses and stores whose `city_id` is not set",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"name_prefix": schema.StringAttribute{
+				MarkdownDescription: "Prefix added to the names of cities and stores in the API, " +
+					"not included in the `name` attribute of the resources",
+				Optional: true,
+			},
+			"name_suffix": schema.StringAttribute{
+				MarkdownDescription: "Suffix added to the names of cities and stores in the API, " +
+					"not included in the `name` attribute of the resources",
+				Optional: true,
+			},
 		},
 	}
 }
@@ -59,31 +161,80 @@ func (p *SendoraCityProvider) Configure(ctx context.Context, req provider.Config
 		)
 	}
 
-	client := client.NewClient(data.BaseUri.ValueString())
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	if data.RelocationMode.IsNull() {
+		data.RelocationMode = types.StringValue(relocationModeInPlace)
+	}
+
+	normalization := defaultAddressNormalization
+	if !data.AddressNormalization.IsNull() {
+		var config SendoraCityProviderAddressNormalizationModel
+		resp.Diagnostics.Append(data.AddressNormalization.As(ctx, &config, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		normalization = addressNormalization{
+			CollapseWhitespace:  config.CollapseWhitespace.IsNull() || config.CollapseWhitespace.ValueBool(),
+			FoldCase:            config.FoldCase.IsNull() || config.FoldCase.ValueBool(),
+			FoldAccents:         config.FoldAccents.IsNull() || config.FoldAccents.ValueBool(),
+			ExpandAbbreviations: config.ExpandAbbreviations.IsNull() || config.ExpandAbbreviations.ValueBool(),
+		}
+	}
+
+	*p.addressNormalization = normalization
+
+	c := client.NewClient(data.BaseUri.ValueString())
+	providerData := &SendoraCityProviderData{
+		Client:                 c,
+		RelocationMode:         data.RelocationMode.ValueString(),
+		MaxInhabitants:         data.MaxInhabitants.ValueInt64(),
+		StoreTypes:             newStoreTypeCatalog(c),
+		AddressNormalization:   normalization,
+		EnforceUniqueAddresses: data.EnforceUniqueAddresses.ValueBool(),
+		EnforceUniqueCityNames: data.EnforceUniqueCityNames.ValueBool(),
+		DefaultCityId:          data.DefaultCityId,
+		NameAffixes: nameAffixes{
+			Prefix: data.NamePrefix.ValueString(),
+			Suffix: data.NameSuffix.ValueString(),
+		},
+	}
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 }
 
 func (p *SendoraCityProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewCityResource,
-		NewHouseResource,
-		NewStoreResource,
+		NewCityMembershipResource,
+		func() resource.Resource {
+			return &HouseResource{addressType: newAddressType(p.addressNormalization)}
+		},
+		NewHousesResource,
+		NewLayoutResource,
+		func() resource.Resource {
+			return &StoreResource{addressType: newAddressType(p.addressNormalization)}
+		},
 	}
 }
 
 func (p *SendoraCityProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
+		NewAddressLookupDataSource,
 		NewCityDataSource,
+		NewCityStatisticsDataSource,
+		NewChangesDataSource,
 		NewHouseDataSource,
+		NewParseAddressDataSource,
 		NewStoreDataSource,
+		NewStoreTypesDataSource,
 	}
 }
 
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
+		normalization := defaultAddressNormalization
 		return &SendoraCityProvider{
-			version: version,
+			version:              version,
+			addressNormalization: &normalization,
 		}
 	}
 }
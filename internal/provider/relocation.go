@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	relocationModeInPlace = "in_place"
+	relocationModeReplace = "replace"
+)
+
+var relocationModes = []string{relocationModeInPlace, relocationModeReplace}
+
+// relocationModeAttribute returns the relocation_mode schema attribute shared
+// by the house and store resources.
+func relocationModeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional: true,
+		MarkdownDescription: "Behavior when `city_id` changes: `in_place` updates the object, `replace` creates a new one. " +
+			"Defaults to the provider `relocation_mode`",
+		Validators: []validator.String{
+			stringvalidator.OneOf(relocationModes...),
+		},
+	}
+}
+
+var _ planmodifier.Int64 = relocationModePlanModifier{}
+
+// relocationModePlanModifier requires the replacement of the resource when
+// city_id changes and relocation_mode is set to replace on the resource. When
+// relocation_mode or city_id is not set, the provider defaults are applied by
+// modifyPlanRelocation, as plan modifiers have no access to the provider
+// configuration.
+type relocationModePlanModifier struct{}
+
+func (m relocationModePlanModifier) Description(ctx context.Context) string {
+	return "Requires replacement when the value changes and relocation_mode is replace."
+}
+
+func (m relocationModePlanModifier) MarkdownDescription(ctx context.Context) string {
+	return "Requires replacement when the value changes and `relocation_mode` is `replace`."
+}
+
+func (m relocationModePlanModifier) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || req.ConfigValue.IsNull() || req.PlanValue.Equal(req.StateValue) {
+		return
+	}
+
+	var mode types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("relocation_mode"), &mode)...)
+	if mode.ValueString() == relocationModeReplace {
+		resp.RequiresReplace = true
+	}
+}
+
+// modifyPlanDefaultCityId plans the provider default_city_id as the city_id of
+// a house or store whose city_id is not set. The city_id is unknown while the
+// default_city_id is, until the provider is configured with its value.
+func modifyPlanDefaultCityId(ctx context.Context, defaultCityId types.Int64, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var cityId types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("city_id"), &cityId)...)
+	if resp.Diagnostics.HasError() || !cityId.IsNull() {
+		return
+	}
+
+	if defaultCityId.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("city_id"), "Missing City",
+			"city_id must be set when the provider sets no default_city_id.")
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("city_id"), defaultCityId)...)
+}
+
+// modifyPlanRelocation requires the replacement of the resource when the
+// planned city_id changes and the relocation_mode of the resource, or the
+// provider default when not set, is replace. It reports whether the resource
+// is replaced, including when relocationModePlanModifier required it already.
+// It runs after modifyPlanDefaultCityId, so that a change of the provider
+// default_city_id relocates the houses and stores using it.
+func modifyPlanRelocation(ctx context.Context, defaultMode string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) bool {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return false
+	}
+
+	var mode types.String
+	var planned, current types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("relocation_mode"), &mode)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("city_id"), &planned)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("city_id"), &current)...)
+	if resp.Diagnostics.HasError() || planned.Equal(current) {
+		return false
+	}
+
+	if mode.IsNull() {
+		mode = types.StringValue(defaultMode)
+	}
+	if mode.ValueString() != relocationModeReplace {
+		return false
+	}
+	if !resp.RequiresReplace.Contains(path.Root("city_id")) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("city_id"))
+	}
+	return true
+}
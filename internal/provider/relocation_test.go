@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestModifyPlanDefaultCityId(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &resource.SchemaResponse{}
+	NewHouseResource().Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	tests := []struct {
+		name          string
+		cityId        types.Int64
+		defaultCityId types.Int64
+		want          types.Int64
+		wantErr       bool
+	}{
+		{name: "city set", cityId: types.Int64Value(1), defaultCityId: types.Int64Value(2), want: types.Int64Value(1)},
+		{name: "default", cityId: types.Int64Null(), defaultCityId: types.Int64Value(2), want: types.Int64Value(2)},
+		{name: "unknown default", cityId: types.Int64Null(), defaultCityId: types.Int64Unknown(), want: types.Int64Unknown()},
+		{name: "no default", cityId: types.Int64Null(), defaultCityId: types.Int64Null(), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan := tfsdk.Plan{Schema: schemaResp.Schema}
+			if diags := plan.Set(ctx, &HouseResourceModel{CityId: test.cityId}); diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+			config := tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}
+
+			req := resource.ModifyPlanRequest{Config: config, Plan: plan}
+			resp := &resource.ModifyPlanResponse{Plan: plan}
+			modifyPlanDefaultCityId(ctx, test.defaultCityId, req, resp)
+			if test.wantErr {
+				if !resp.Diagnostics.HasError() {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+			}
+
+			var got types.Int64
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("city_id"), &got)...)
+			if !got.Equal(test.want) {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// idsToInt64StateUpgrader returns the state upgrader from schema version 0,
+// where identifiers were strings, to version 1 where they are numbers. The
+// given attributes are converted, be they single identifiers, sets or maps of
+// them, as well as the id of every element of the given nested list, set or map
+// attributes. Other values are copied over untouched.
+func idsToInt64StateUpgrader(attributes []string, nested []string) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			if req.RawState == nil || req.RawState.JSON == nil {
+				resp.Diagnostics.AddError("Unable to Upgrade State",
+					"Expected a JSON state written by Terraform 0.12 or later.")
+				return
+			}
+
+			decoder := json.NewDecoder(bytes.NewReader(req.RawState.JSON))
+			decoder.UseNumber()
+			state := map[string]any{}
+			if err := decoder.Decode(&state); err != nil {
+				resp.Diagnostics.AddError("Unable to Upgrade State",
+					fmt.Sprintf("Unable to unmarshal prior state, got error: %s", err))
+				return
+			}
+
+			for _, attribute := range attributes {
+				if err := convertIdToInt64(state, attribute); err != nil {
+					resp.Diagnostics.AddError("Unable to Upgrade State", err.Error())
+					return
+				}
+			}
+			for _, attribute := range nested {
+				var elements []any
+				switch value := state[attribute].(type) {
+				case []any:
+					elements = value
+				case map[string]any:
+					for _, key := range sortedKeys(value) {
+						elements = append(elements, value[key])
+					}
+				}
+				for _, element := range elements {
+					object, ok := element.(map[string]any)
+					if !ok {
+						continue
+					}
+					if err := convertIdToInt64(object, "id"); err != nil {
+						resp.Diagnostics.AddError("Unable to Upgrade State",
+							fmt.Sprintf("%s: %s", attribute, err))
+						return
+					}
+				}
+			}
+
+			upgraded, err := json.Marshal(state)
+			if err != nil {
+				resp.Diagnostics.AddError("Unable to Upgrade State",
+					fmt.Sprintf("Unable to marshal upgraded state, got error: %s", err))
+				return
+			}
+			resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+		},
+	}
+}
+
+// convertIdToInt64 replaces the string identifier stored under key, or each
+// identifier of the set or map stored under key, with its numeric value.
+// Missing, null and empty identifiers become null, and are dropped from sets
+// and maps.
+func convertIdToInt64(object map[string]any, key string) error {
+	if values, ok := object[key].(map[string]any); ok {
+		converted := map[string]any{}
+		for name, value := range values {
+			id, err := int64Id(fmt.Sprintf("%s[%q]", key, name), value)
+			if err != nil {
+				return err
+			}
+			if id != nil {
+				converted[name] = id
+			}
+		}
+		object[key] = converted
+		return nil
+	}
+	if values, ok := object[key].([]any); ok {
+		converted := []any{}
+		for _, value := range values {
+			id, err := int64Id(key, value)
+			if err != nil {
+				return err
+			}
+			if id != nil {
+				converted = append(converted, id)
+			}
+		}
+		object[key] = converted
+		return nil
+	}
+
+	id, err := int64Id(key, object[key])
+	if err != nil {
+		return err
+	}
+	if _, ok := object[key]; ok {
+		object[key] = id
+	}
+	return nil
+}
+
+// int64Id returns the numeric value of a string identifier, nil when empty.
+// Values that are not strings are returned unchanged.
+func int64Id(key string, value any) (any, error) {
+	id, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	if id == "" {
+		return nil, nil
+	}
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return nil, fmt.Errorf("%s %q is not a number", key, id)
+	}
+	return json.Number(id), nil
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestIdsToInt64StateUpgrader(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes []string
+		nested     []string
+		state      string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "string id",
+			attributes: []string{"id", "city_id"},
+			state:      `{"id":"12","city_id":"3","address":"1 rue de la Paix"}`,
+			want:       `{"id":12,"city_id":3,"address":"1 rue de la Paix"}`,
+		},
+		{
+			name:       "empty id",
+			attributes: []string{"id"},
+			state:      `{"id":"","name":"Paris"}`,
+			want:       `{"id":null,"name":"Paris"}`,
+		},
+		{
+			name:       "null and missing ids",
+			attributes: []string{"id", "city_id"},
+			state:      `{"id":null}`,
+			want:       `{"id":null}`,
+		},
+		{
+			name:       "non-numeric id",
+			attributes: []string{"id"},
+			state:      `{"id":"Paris"}`,
+			wantErr:    `id "Paris" is not a number`,
+		},
+		{
+			name:       "set of ids",
+			attributes: []string{"house_ids"},
+			state:      `{"house_ids":["4","5"]}`,
+			want:       `{"house_ids":[4,5]}`,
+		},
+		{
+			name:       "map of ids",
+			attributes: []string{"city_ids", "house_ids"},
+			state:      `{"id":"uuid","city_ids":{"Paris":"1","Troyes":""},"house_ids":null}`,
+			want:       `{"id":"uuid","city_ids":{"Paris":1},"house_ids":null}`,
+		},
+		{
+			name:       "non-numeric id in a map",
+			attributes: []string{"city_ids"},
+			state:      `{"city_ids":{"Paris":"x"}}`,
+			wantErr:    `city_ids["Paris"] "x" is not a number`,
+		},
+		{
+			name:       "nested house and store elements",
+			attributes: []string{"id"},
+			nested:     []string{"house", "store"},
+			state: `{"id":"1","house":[{"id":"2","address":"a","inhabitants":3}],` +
+				`"store":[{"id":"","name":"b"},{"id":"4","name":"c"}]}`,
+			want: `{"id":1,"house":[{"id":2,"address":"a","inhabitants":3}],` +
+				`"store":[{"id":null,"name":"b"},{"id":4,"name":"c"}]}`,
+		},
+		{
+			name:   "null nested attribute",
+			nested: []string{"house"},
+			state:  `{"house":null}`,
+			want:   `{"house":null}`,
+		},
+		{
+			name:   "nested map elements",
+			nested: []string{"houses"},
+			state:  `{"houses":{"a":{"id":"7","inhabitants":1}}}`,
+			want:   `{"houses":{"a":{"id":7,"inhabitants":1}}}`,
+		},
+		{
+			name:    "non-numeric nested id",
+			nested:  []string{"store"},
+			state:   `{"store":[{"id":"x"}]}`,
+			wantErr: `store: id "x" is not a number`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(test.state)}}
+			resp := &resource.UpgradeStateResponse{}
+			idsToInt64StateUpgrader(test.attributes, test.nested).StateUpgrader(context.Background(), req, resp)
+
+			if test.wantErr != "" {
+				if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), test.wantErr) {
+					t.Errorf("got errors %v, want error containing %q", resp.Diagnostics, test.wantErr)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+			}
+
+			var got, want any
+			if err := json.Unmarshal(resp.DynamicValue.JSON, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(test.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %s, want %s", resp.DynamicValue.JSON, test.want)
+			}
+		})
+	}
+}
+
+func TestIdsToInt64StateUpgraderMissingState(t *testing.T) {
+	resp := &resource.UpgradeStateResponse{}
+	idsToInt64StateUpgrader([]string{"id"}, nil).StateUpgrader(context.Background(), resource.UpgradeStateRequest{}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("got no error, want one")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -21,16 +20,18 @@ func NewStoreDataSource() datasource.DataSource {
 }
 
 type StoreDataSource struct {
-	client *client.SendoraCityClient
-	url    string
+	client      *client.SendoraCityClient
+	url         string
+	nameAffixes nameAffixes
 }
 
 type StoreDataSourceModel struct {
-	Id      types.String `tfsdk:"id"`
-	CityId  types.String `tfsdk:"city_id"`
-	Address types.String `tfsdk:"address"`
-	Name    types.String `tfsdk:"name"`
-	Type    types.String `tfsdk:"type"`
+	Id        types.Int64  `tfsdk:"id"`
+	CityId    types.Int64  `tfsdk:"city_id"`
+	Address   types.String `tfsdk:"address"`
+	Name      types.String `tfsdk:"name"`
+	Type      types.String `tfsdk:"type"`
+	CreatedAt types.String `tfsdk:"created_at"`
 }
 
 func (d *StoreDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -42,11 +43,12 @@ func (d *StoreDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 		MarkdownDescription: "Store data source",
 
 		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
+			"id": schema.Int64Attribute{
 				MarkdownDescription: "Store identifier",
 				Optional:            true,
+				Validators:          idValidators(),
 			},
-			"city_id": schema.StringAttribute{
+			"city_id": schema.Int64Attribute{
 				MarkdownDescription: "Store city identifier",
 				Computed:            true,
 			},
@@ -62,6 +64,10 @@ func (d *StoreDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 				MarkdownDescription: "Store type",
 				Computed:            true,
 			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Store creation timestamp (RFC3339)",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -71,18 +77,19 @@ func (d *StoreDataSource) Configure(ctx context.Context, req datasource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SendoraCityClient)
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.SendoraCityClient, got: %T."+
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
 				"Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = providerData.Client
+	d.nameAffixes = providerData.NameAffixes
 	d.url = "stores"
 }
 
@@ -95,7 +102,7 @@ func (d *StoreDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	res, err := d.client.DoRead(fmt.Sprintf("%s/%s", d.url, data.Id.ValueString()))
+	res, err := d.client.DoRead(fmt.Sprintf("%s/%d", d.url, data.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to read store, got error: %s", err))
@@ -117,10 +124,11 @@ func (d *StoreDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	data.CityId = types.StringValue(strconv.Itoa(store.CityId))
+	data.CityId = types.Int64Value(int64(store.CityId))
 	data.Address = types.StringValue(store.Address)
-	data.Name = types.StringValue(store.Name)
+	data.Name = d.nameAffixes.configNameValue(types.StringValue(store.Name))
 	data.Type = types.StringValue(store.Type)
+	data.CreatedAt = createdAtValue(store.Timestamp)
 
 	tflog.Trace(ctx, "read store from a data source")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -18,6 +18,7 @@ func TestAccStoreDataSource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("sendoracity_store.test", "id"),
 					resource.TestCheckResourceAttrSet("sendoracity_store.test", "city_id"),
+					resource.TestCheckResourceAttrSet("data.sendoracity_store.test", "created_at"),
 					resource.TestCheckResourceAttr("sendoracity_store.test", "address", "data-store-test-address"),
 					resource.TestCheckResourceAttr("sendoracity_store.test", "name", "Store 1"),
 					resource.TestCheckResourceAttr("sendoracity_store.test", "type", "Other"),
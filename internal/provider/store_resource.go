@@ -8,13 +8,12 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
@@ -22,22 +21,40 @@ import (
 
 var _ resource.Resource = &StoreResource{}
 var _ resource.ResourceWithImportState = &StoreResource{}
+var _ resource.ResourceWithUpgradeState = &StoreResource{}
+var _ resource.ResourceWithModifyPlan = &StoreResource{}
 
 func NewStoreResource() resource.Resource {
 	return &StoreResource{}
 }
 
 type StoreResource struct {
-	client *client.SendoraCityClient
-	url    string
+	client                 *client.SendoraCityClient
+	url                    string
+	relocationMode         string
+	storeTypes             *storeTypeCatalog
+	addressNormalization   addressNormalization
+	enforceUniqueAddresses bool
+	defaultCityId          types.Int64
+	nameAffixes            nameAffixes
+	addressType            AddressType
 }
 
 type StoreResourceModel struct {
-	Id      types.String `tfsdk:"id"`
-	CityId  types.String `tfsdk:"city_id"`
-	Address types.String `tfsdk:"address"`
-	Name    types.String `tfsdk:"name"`
-	Type    types.String `tfsdk:"type"`
+	Id                 types.Int64    `tfsdk:"id"`
+	CityId             types.Int64    `tfsdk:"city_id"`
+	Address            AddressValue   `tfsdk:"address"`
+	NormalizedAddress  types.String   `tfsdk:"normalized_address"`
+	StreetNumber       types.String   `tfsdk:"street_number"`
+	StreetType         types.String   `tfsdk:"street_type"`
+	StreetName         types.String   `tfsdk:"street_name"`
+	PostalCode         types.String   `tfsdk:"postal_code"`
+	Name               types.String   `tfsdk:"name"`
+	Type               StoreTypeValue `tfsdk:"type"`
+	CreatedAt          types.String   `tfsdk:"created_at"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	AdoptExisting      types.Bool     `tfsdk:"adopt_existing"`
+	RelocationMode     types.String   `tfsdk:"relocation_mode"`
 }
 
 func (r *StoreResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -47,55 +64,105 @@ func (r *StoreResource) Metadata(ctx context.Context, req resource.MetadataReque
 func (r *StoreResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Store resource",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
+			"id": schema.Int64Attribute{
 				Computed:            true,
 				MarkdownDescription: "Store identifier",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Store creation timestamp (RFC3339)",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"city_id": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "Store city identifier",
+			"city_id": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Store city identifier. Defaults to the provider `default_city_id`",
+				PlanModifiers: []planmodifier.Int64{
+					relocationModePlanModifier{},
+				},
+				Validators: idValidators(),
 			},
 			"address": schema.StringAttribute{
+				CustomType:          r.addressType,
 				Required:            true,
-				MarkdownDescription: "Store address",
+				MarkdownDescription: "Store address. Changes made outside of Terraform that keep the same `normalized_address` are ignored",
+				Validators:          textValidators(),
+			},
+			"normalized_address": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Store address in its canonical form, following the provider `address_normalization`",
+			},
+			"street_number": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Street number parsed from the address, with its repetition index such as `bis`",
+			},
+			"street_type": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Street type parsed from the address, in its full form, lowercase and without accents, such as `avenue`",
+			},
+			"street_name": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Street name parsed from the address",
+			},
+			"postal_code": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Postal code parsed from the address, when present",
 			},
 			"name": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "Store name",
+				Validators:          textValidators(),
 			},
 			"type": schema.StringAttribute{
+				CustomType:          StoreTypeType{},
 				Required:            true,
-				MarkdownDescription: "Store type",
-				Validators: []validator.String{
-					stringvalidator.OneOf("Food", "Sports", "Clothes", "Electronics", "Other"),
-				},
+				MarkdownDescription: "Store type, in any case",
+				Validators:          textValidators(),
 			},
+			"deletion_protection": deletionProtectionAttribute(),
+			"adopt_existing":      adoptExistingAttribute(),
+			"relocation_mode":     relocationModeAttribute(),
 		},
 	}
 }
 
+func (r *StoreResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: idsToInt64StateUpgrader([]string{"id", "city_id"}, nil),
+	}
+}
+
 func (r *StoreResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SendoraCityClient)
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.SendoraCityClient, got: %T."+
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
 				"Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Client
+	r.relocationMode = providerData.RelocationMode
+	r.addressNormalization = providerData.AddressNormalization
+	r.enforceUniqueAddresses = providerData.EnforceUniqueAddresses
+	r.defaultCityId = providerData.DefaultCityId
+	r.storeTypes = providerData.StoreTypes
+	r.nameAffixes = providerData.NameAffixes
 	r.url = "stores"
 }
 
@@ -108,18 +175,31 @@ func (r *StoreResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	cityId, err := strconv.Atoi(data.CityId.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error",
-			fmt.Sprintf("Unable to convert city_id to int, got error: %s", err))
-		return
-	}
-
-	body := &Store{
-		CityId:  cityId,
-		Address: data.Address.ValueString(),
-		Name:    data.Name.ValueString(),
-		Type:    data.Type.ValueString(),
+	body := storeBody(data, r.storeTypes.list(ctx))
+	body.Name = r.nameAffixes.apiName(body.Name)
+
+	if data.AdoptExisting.ValueBool() {
+		existing, err := adoptableStore(r.client, body.CityId, body.Name)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error",
+				fmt.Sprintf("Unable to look up an existing store to adopt, got error: %s", err))
+			return
+		}
+		if existing != nil {
+			id := strconv.Itoa(existing.Id)
+			if err = updateObject(r.client, fmt.Sprintf("%s/%s", r.url, id), body); err != nil {
+				resp.Diagnostics.AddError("Client Error",
+					fmt.Sprintf("Unable to update adopted store with id %s, got error: %s", id, err))
+				return
+			}
+
+			data.Id = types.Int64Value(int64(existing.Id))
+			data.CreatedAt = createdAtValue(existing.Timestamp)
+
+			tflog.Trace(ctx, "adopted an existing store resource", map[string]any{"id": id})
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
 	}
 
 	jsonBody, err := json.Marshal(body)
@@ -151,7 +231,8 @@ func (r *StoreResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	data.Id = types.StringValue(strconv.Itoa(store.Id))
+	data.Id = types.Int64Value(int64(store.Id))
+	data.CreatedAt = createdAtValue(store.Timestamp)
 
 	tflog.Trace(ctx, "created a store resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -166,7 +247,7 @@ func (r *StoreResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	res, err := r.client.DoRead(fmt.Sprintf("%s/%s", r.url, data.Id.ValueString()))
+	res, err := r.client.DoRead(fmt.Sprintf("%s/%d", r.url, data.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to read store, got error: %s", err))
@@ -193,10 +274,19 @@ func (r *StoreResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	data.CityId = types.StringValue(strconv.Itoa(store.CityId))
-	data.Address = types.StringValue(store.Address)
-	data.Name = types.StringValue(store.Name)
-	data.Type = types.StringValue(store.Type)
+	priorAddress := data.Address
+	refreshStoreModel(data, store)
+	data.Name = r.nameAffixes.configNameValue(data.Name)
+	address, diags := keptAddress(ctx, priorAddress, data.Address)
+	resp.Diagnostics.Append(diags...)
+	data.NormalizedAddress = r.addressNormalization.normalizedAddressValue(address.StringValue)
+	data.StreetNumber, data.StreetType, data.StreetName, data.PostalCode = addressComponentValues(address.StringValue)
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
+	}
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -210,19 +300,8 @@ func (r *StoreResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	cityId, err := strconv.Atoi(data.CityId.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error",
-			fmt.Sprintf("Unable to convert city_id to int, got error: %s", err))
-		return
-	}
-
-	body := &Store{
-		CityId:  cityId,
-		Address: data.Address.ValueString(),
-		Name:    data.Name.ValueString(),
-		Type:    data.Type.ValueString(),
-	}
+	body := storeBody(data, r.storeTypes.list(ctx))
+	body.Name = r.nameAffixes.apiName(body.Name)
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
@@ -231,7 +310,7 @@ func (r *StoreResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	id := data.Id.ValueString()
+	id := strconv.FormatInt(data.Id.ValueInt64(), 10)
 	if _, err = r.client.DoUpdate(fmt.Sprintf("%s/%s", r.url, id), jsonBody); err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to update store with id %s, got error: %s", id, err))
@@ -250,7 +329,12 @@ func (r *StoreResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	id := data.Id.ValueString()
+	id := strconv.FormatInt(data.Id.ValueInt64(), 10)
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.Append(deletionProtectionError("store", id))
+		return
+	}
+
 	if _, err := r.client.DoDelete(fmt.Sprintf("%s/%s", r.url, id)); err != nil {
 		resp.Diagnostics.AddError("Client Error",
 			fmt.Sprintf("Unable to delete store with id %s, got error: %s", id, err))
@@ -258,6 +342,31 @@ func (r *StoreResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 }
 
+func (r *StoreResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanDefaultCityId(ctx, r.defaultCityId, req, resp)
+	replaced := modifyPlanRelocation(ctx, r.relocationMode, req, resp)
+	modifyPlanDeletionProtection(ctx, "store", replaced, req, resp)
+	modifyPlanAddress(ctx, r.addressNormalization, req, resp)
+	if r.enforceUniqueAddresses {
+		modifyPlanUniqueAddress(ctx, r.client, r.addressNormalization, r.nameAffixes, "store", req, resp)
+	}
+
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var storeType StoreTypeValue
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("type"), &storeType)...)
+	resp.Diagnostics.Append(validateStoreType(r.storeTypes.list(ctx), path.Root("type"), storeType)...)
+}
+
 func (r *StoreResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id, err := resolveImportId(r.client, r.nameAffixes, "store", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import Identifier",
+			fmt.Sprintf("Unable to import store, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), int64(id))...)
 }
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -17,8 +18,10 @@ func TestAccStoreResource(t *testing.T) {
 				Config: testAccStoreResourceConfig("store-test-city-name", "store-test-address-1"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("sendoracity_store.test", "id"),
+					resource.TestCheckResourceAttrSet("sendoracity_store.test", "created_at"),
 					resource.TestCheckResourceAttrSet("sendoracity_store.test", "city_id"),
 					resource.TestCheckResourceAttr("sendoracity_store.test", "address", "store-test-address-1"),
+					resource.TestCheckResourceAttr("sendoracity_store.test", "normalized_address", "store test address 1"),
 					resource.TestCheckResourceAttr("sendoracity_store.test", "name", "Store 1"),
 					resource.TestCheckResourceAttr("sendoracity_store.test", "type", "Other"),
 				),
@@ -29,6 +32,13 @@ func TestAccStoreResource(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			// ImportState by natural key testing
+			{
+				ResourceName:      "sendoracity_store.test",
+				ImportState:       true,
+				ImportStateId:     "store:store-test-city-name/Store 1",
+				ImportStateVerify: true,
+			},
 			// Update and Read testing
 			{
 				Config: testAccStoreResourceConfig("store-test-city-name", "store-test-address-2"),
@@ -41,6 +51,29 @@ func TestAccStoreResource(t *testing.T) {
 	})
 }
 
+func TestAccStoreResourceTypeCase(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Misspelled type testing
+			{
+				Config:      testAccStoreResourceTypeConfig("store-type-test-city-name", "Fodo"),
+				ExpectError: regexp.MustCompile(`Did you mean "Food"\?`),
+			},
+			// Create and Read testing, the canonical type returned by the API
+			// does not show as a diff
+			{
+				Config: testAccStoreResourceTypeConfig("store-type-test-city-name", "food"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_store.test", "type", "food"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
 func testAccStoreResourceConfig(cityName, address string) string {
 	return fmt.Sprintf(`
 resource "sendoracity_city" "test" {
@@ -56,3 +89,125 @@ resource "sendoracity_store" "test" {
 }
 `, cityName, address)
 }
+
+func testAccStoreResourceTypeConfig(cityName, storeType string) string {
+	return fmt.Sprintf(`
+resource "sendoracity_city" "test" {
+  name      = "%s"
+  touristic = true
+}
+
+resource "sendoracity_store" "test" {
+  city_id = sendoracity_city.test.id
+  address = "store-type-test-address"
+  name    = "Store 1"
+  type    = "%s"
+}
+`, cityName, storeType)
+}
+
+func TestAccStoreResourceNameAffixes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Missing city testing
+			{
+				Config: `
+resource "sendoracity_store" "test" {
+  address = "store-test-name-affixes"
+  name    = "Store 1"
+  type    = "Other"
+}
+`,
+				ExpectError: regexp.MustCompile(`city_id must be set when the provider sets no default_city_id`),
+			},
+			// Create and Read testing, the state keeps the names without affixes
+			{
+				Config: `
+provider "sendoracity" {
+  name_prefix = "test-"
+  name_suffix = "-ephemeral"
+}
+
+resource "sendoracity_city" "test" {
+  name      = "store-test-name-affixes"
+  touristic = false
+}
+
+resource "sendoracity_store" "test" {
+  city_id = sendoracity_city.test.id
+  address = "store-test-name-affixes"
+  name    = "Store 1"
+  type    = "Other"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_city.test", "name", "store-test-name-affixes"),
+					resource.TestCheckResourceAttr("sendoracity_store.test", "name", "Store 1"),
+				),
+			},
+			// ImportState testing by natural key, with the configured names
+			{
+				ResourceName:      "sendoracity_city.test",
+				ImportState:       true,
+				ImportStateId:     "city:store-test-name-affixes",
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "sendoracity_store.test",
+				ImportState:       true,
+				ImportStateId:     "store:store-test-name-affixes/Store 1",
+				ImportStateVerify: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccStoreResourceDeletionProtection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create a protected store
+			{
+				Config: testAccStoreResourceDeletionProtectionConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_store.test", "deletion_protection", "true"),
+				),
+			},
+			// Destroying the protected store fails
+			{
+				Config:      testAccStoreResourceDeletionProtectionConfig(true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Deletion Protection Enabled"),
+			},
+			// Disable the protection so the store can be deleted
+			{
+				Config: testAccStoreResourceDeletionProtectionConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("sendoracity_store.test", "deletion_protection", "false"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccStoreResourceDeletionProtectionConfig(protected bool) string {
+	return fmt.Sprintf(`
+resource "sendoracity_city" "test" {
+  name      = "store-test-deletion-protection"
+  touristic = false
+}
+
+resource "sendoracity_store" "test" {
+  city_id             = sendoracity_city.test.id
+  address             = "store-test-deletion-protection-address"
+  name                = "Store 1"
+  type                = "Other"
+  deletion_protection = %t
+}
+`, protected)
+}
@@ -0,0 +1,259 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+var _ basetypes.StringTypable = StoreTypeType{}
+
+// StoreTypeType is the type of store type attributes. Values are accepted in
+// any case and compare equal to their canonical form, so that the casing
+// returned by the API never shows as a diff.
+type StoreTypeType struct {
+	basetypes.StringType
+}
+
+func (t StoreTypeType) Equal(o attr.Type) bool {
+	other, ok := o.(StoreTypeType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t StoreTypeType) String() string {
+	return "StoreTypeType"
+}
+
+func (t StoreTypeType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return StoreTypeValue{StringValue: in}, nil
+}
+
+func (t StoreTypeType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	stringValuable, diags := t.ValueFromString(ctx, stringValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting StringValue to StringValuable: %v", diags)
+	}
+	return stringValuable, nil
+}
+
+func (t StoreTypeType) ValueType(ctx context.Context) attr.Value {
+	return StoreTypeValue{}
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = StoreTypeValue{}
+
+// StoreTypeValue is a store type, equal to any other casing of itself.
+type StoreTypeValue struct {
+	basetypes.StringValue
+}
+
+func (v StoreTypeValue) Equal(o attr.Value) bool {
+	other, ok := o.(StoreTypeValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v StoreTypeValue) Type(ctx context.Context) attr.Type {
+	return StoreTypeType{}
+}
+
+func (v StoreTypeValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(StoreTypeValue)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got: %T. Please report this issue to the provider developers.", v, newValuable))
+		return false, diags
+	}
+	return strings.EqualFold(v.ValueString(), newValue.ValueString()), diags
+}
+
+// storeTypeValue converts an API store type to a Terraform value, null when
+// omitted.
+func storeTypeValue(value string) StoreTypeValue {
+	return StoreTypeValue{StringValue: stringValue(value)}
+}
+
+// refreshedStoreType returns the store type read from the API, keeping the
+// prior value when it only differs in case. Set elements are not compared
+// semantically by the framework, so nested stores are refreshed this way.
+func refreshedStoreType(prior StoreTypeValue, current string) StoreTypeValue {
+	if strings.EqualFold(prior.ValueString(), current) {
+		return prior
+	}
+	return storeTypeValue(current)
+}
+
+// canonicalStoreType returns the store type matching value regardless of case.
+func canonicalStoreType(value string, storeTypes []string) (string, bool) {
+	for _, storeType := range storeTypes {
+		if strings.EqualFold(storeType, value) {
+			return storeType, true
+		}
+	}
+	return "", false
+}
+
+// storeTypeBody returns the canonical form of a store type for the API. Values
+// matching no store type are sent as is for the API to reject them.
+func storeTypeBody(value string, storeTypes []string) string {
+	if storeType, ok := canonicalStoreType(value, storeTypes); ok {
+		return storeType
+	}
+	return value
+}
+
+// storeTypeError describes an unknown store type, suggesting the closest one
+// when the value looks like a misspelling.
+func storeTypeError(value string, storeTypes []string) string {
+	message := fmt.Sprintf("must be one of %s, got: %q", strings.Join(storeTypes, ", "), value)
+	if suggestion, ok := suggestStoreType(value, storeTypes); ok {
+		message += fmt.Sprintf(". Did you mean %q?", suggestion)
+	}
+	return message
+}
+
+// suggestStoreType returns the store type closest to value, provided that it
+// is at most two edits or a third of its letters away.
+func suggestStoreType(value string, storeTypes []string) (string, bool) {
+	suggestion, best := "", -1
+	for _, storeType := range storeTypes {
+		distance := levenshtein(strings.ToLower(value), strings.ToLower(storeType))
+		if best < 0 || distance < best {
+			suggestion, best = storeType, distance
+		}
+	}
+	if best < 0 || (best > 2 && best*3 > len(suggestion)) {
+		return "", false
+	}
+	return suggestion, true
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = smallest(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
+
+// smallest returns the smallest of the given values.
+func smallest(first int, others ...int) int {
+	for _, value := range others {
+		if value < first {
+			first = value
+		}
+	}
+	return first
+}
+
+// validateStoreType checks a store type against the store types accepted by
+// the API, in any case.
+func validateStoreType(storeTypes []string, attribute path.Path, value StoreTypeValue) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if value.IsNull() || value.IsUnknown() {
+		return diags
+	}
+
+	if _, ok := canonicalStoreType(value.ValueString(), storeTypes); !ok {
+		diags.AddAttributeError(attribute, "Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s", attribute, storeTypeError(value.ValueString(), storeTypes)))
+	}
+	return diags
+}
+
+// storeTypeCatalog holds the store types accepted by the API. They are fetched
+// once per provider instance, the first time they are needed, and default to
+// builtinStoreTypes when the API does not provide them. A failed request is
+// retried the next time they are needed.
+type storeTypeCatalog struct {
+	client     *client.SendoraCityClient
+	mu         sync.Mutex
+	loaded     bool
+	storeTypes []string
+	fromAPI    bool
+}
+
+func newStoreTypeCatalog(c *client.SendoraCityClient) *storeTypeCatalog {
+	return &storeTypeCatalog{client: c}
+}
+
+// list returns the store types accepted by the API. A nil catalog, used before
+// the provider is configured, lists the built-in store types.
+func (c *storeTypeCatalog) list(ctx context.Context) []string {
+	storeTypes, _ := c.source(ctx)
+	return storeTypes
+}
+
+// source returns the store types accepted by the API, and whether they were
+// published by the API rather than built in.
+func (c *storeTypeCatalog) source(ctx context.Context) ([]string, bool) {
+	if c == nil {
+		return builtinStoreTypes, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loaded {
+		return c.storeTypes, c.fromAPI
+	}
+
+	storeTypes, err := listStoreTypes(c.client)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to fetch store types from the API, using the built-in list until the next attempt", map[string]any{
+			"error":       err.Error(),
+			"store_types": builtinStoreTypes,
+		})
+		return builtinStoreTypes, false
+	}
+
+	c.loaded = true
+	if len(storeTypes) == 0 {
+		tflog.Warn(ctx, "The API does not publish store types, using the built-in list", map[string]any{
+			"store_types": builtinStoreTypes,
+		})
+		c.storeTypes = builtinStoreTypes
+		return c.storeTypes, false
+	}
+	c.storeTypes, c.fromAPI = storeTypes, true
+	return c.storeTypes, true
+}
@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+func TestStoreTypeSemanticEquals(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "same value", a: "Food", b: "Food", want: true},
+		{name: "lowercase", a: "Food", b: "food", want: true},
+		{name: "uppercase", a: "sports", b: "SPORTS", want: true},
+		{name: "different types", a: "Food", b: "Other", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := StoreTypeValue{StringValue: types.StringValue(test.a)}
+			b := StoreTypeValue{StringValue: types.StringValue(test.b)}
+			got, diags := a.StringSemanticEquals(context.Background(), b)
+			if diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+			if got != test.want {
+				t.Errorf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStoreTypeBody(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Food", want: "Food"},
+		{value: "food", want: "Food"},
+		{value: "ELECTRONICS", want: "Electronics"},
+		{value: "Toys", want: "Toys"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if got := storeTypeBody(test.value, builtinStoreTypes); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSuggestStoreType(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   string
+		wantOk bool
+	}{
+		{value: "Fod", want: "Food", wantOk: true},
+		{value: "sport", want: "Sports", wantOk: true},
+		{value: "Clotes", want: "Clothes", wantOk: true},
+		{value: "electonics", want: "Electronics", wantOk: true},
+		{value: "Toys"},
+		{value: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got, ok := suggestStoreType(test.value, builtinStoreTypes)
+			if got != test.want || ok != test.wantOk {
+				t.Errorf("got %q, %t, want %q, %t", got, ok, test.want, test.wantOk)
+			}
+		})
+	}
+}
+
+func TestValidateStoreType(t *testing.T) {
+	tests := []struct {
+		name       string
+		storeTypes []string
+		value      types.String
+		wantErr    string
+	}{
+		{name: "canonical", storeTypes: builtinStoreTypes, value: types.StringValue("Food")},
+		{name: "lowercase", storeTypes: builtinStoreTypes, value: types.StringValue("clothes")},
+		{name: "null", storeTypes: builtinStoreTypes, value: types.StringNull()},
+		{name: "unknown", storeTypes: builtinStoreTypes, value: types.StringUnknown()},
+		{name: "misspelled", storeTypes: builtinStoreTypes, value: types.StringValue("Fodo"), wantErr: `Did you mean "Food"?`},
+		{name: "unknown type", storeTypes: builtinStoreTypes, value: types.StringValue("Toys"), wantErr: `got: "Toys"`},
+		{name: "type published by the API", storeTypes: []string{"Food", "Toys"}, value: types.StringValue("toys")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diags := validateStoreType(test.storeTypes, path.Root("type"), StoreTypeValue{StringValue: test.value})
+			if test.wantErr == "" {
+				if diags.HasError() {
+					t.Errorf("unexpected errors: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || !strings.Contains(diags[0].Detail(), test.wantErr) {
+				t.Errorf("got errors %v, want error containing %q", diags, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestStoreTypeCatalog(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		want         []string
+		wantFromAPI  bool
+		wantRequests int
+	}{
+		{name: "published", status: http.StatusOK, body: `["Food","Toys"]`, want: []string{"Food", "Toys"}, wantFromAPI: true, wantRequests: 1},
+		{name: "not published", status: http.StatusNotFound, want: builtinStoreTypes, wantRequests: 1},
+		{name: "empty", status: http.StatusOK, body: `[]`, want: builtinStoreTypes, wantRequests: 1},
+		{name: "server error", status: http.StatusInternalServerError, want: builtinStoreTypes, wantRequests: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			catalog := newStoreTypeCatalog(client.NewClient(server.URL))
+			for i := 0; i < 2; i++ {
+				if got := catalog.list(context.Background()); !reflect.DeepEqual(got, test.want) {
+					t.Errorf("got %v, want %v", got, test.want)
+				}
+			}
+			if catalog.fromAPI != test.wantFromAPI {
+				t.Errorf("got fromAPI %t, want %t", catalog.fromAPI, test.wantFromAPI)
+			}
+			if requests != test.wantRequests {
+				t.Errorf("got %d requests, want %d", requests, test.wantRequests)
+			}
+		})
+	}
+}
+
+func TestStoreTypeCatalogAfterError(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`["Food","Toys"]`))
+	}))
+	defer server.Close()
+
+	catalog := newStoreTypeCatalog(client.NewClient(server.URL))
+	if got := catalog.list(context.Background()); !reflect.DeepEqual(got, builtinStoreTypes) {
+		t.Errorf("got %v, want %v", got, builtinStoreTypes)
+	}
+	for i := 0; i < 2; i++ {
+		if got, want := catalog.list(context.Background()), []string{"Food", "Toys"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2", requests)
+	}
+}
+
+func TestStoreTypeCatalogNil(t *testing.T) {
+	var catalog *storeTypeCatalog
+	if got := catalog.list(context.Background()); !reflect.DeepEqual(got, builtinStoreTypes) {
+		t.Errorf("got %v, want %v", got, builtinStoreTypes)
+	}
+}
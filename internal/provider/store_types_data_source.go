@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &StoreTypesDataSource{}
+
+func NewStoreTypesDataSource() datasource.DataSource {
+	return &StoreTypesDataSource{}
+}
+
+type StoreTypesDataSource struct {
+	storeTypes *storeTypeCatalog
+}
+
+type StoreTypesDataSourceModel struct {
+	Types  types.List   `tfsdk:"types"`
+	Source types.String `tfsdk:"source"`
+}
+
+func (d *StoreTypesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_store_types"
+}
+
+func (d *StoreTypesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Store types data source, listing the store types accepted by the API",
+
+		Attributes: map[string]schema.Attribute{
+			"types": schema.ListAttribute{
+				MarkdownDescription: "Store types, in their canonical form",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"source": schema.StringAttribute{
+				MarkdownDescription: "Origin of the store types: `api`, or `builtin` when the API does not publish them",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *StoreTypesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SendoraCityProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SendoraCityProviderData, got: %T."+
+				"Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.storeTypes = providerData.StoreTypes
+}
+
+func (d *StoreTypesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data StoreTypesDataSourceModel
+
+	list, fromAPI := d.storeTypes.source(ctx)
+	storeTypes, diags := types.ListValueFrom(ctx, types.StringType, list)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Types = storeTypes
+	data.Source = types.StringValue("builtin")
+	if fromAPI {
+		data.Source = types.StringValue("api")
+	}
+
+	tflog.Trace(ctx, "read store types from a data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
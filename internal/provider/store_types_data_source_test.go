@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccStoreTypesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccStoreTypesDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.sendoracity_store_types.test", "source"),
+					resource.TestCheckTypeSetElemAttr("data.sendoracity_store_types.test", "types.*", "Food"),
+				),
+			},
+		},
+	})
+}
+
+const testAccStoreTypesDataSourceConfig = `
+data "sendoracity_store_types" "test" {}
+`
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// apiTimeZone is the time zone of the API database, set in api/init.sql. The
+// Timestamp columns have no zone and hold local times in this zone.
+const apiTimeZone = "Europe/Paris"
+
+// apiLocation is the location of apiTimeZone. The time zone database is
+// embedded so that loading it cannot fail.
+var apiLocation = mustLoadLocation(apiTimeZone)
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+// timestampLayouts lists the formats the API may use to serialize the Timestamp
+// column. Timestamps without a zone are local times in apiLocation.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseTimestamp parses a timestamp returned by the API and converts it to UTC.
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, apiLocation); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse timestamp %q", value)
+}
+
+// apiTimestamp formats a time as a timestamp without zone in apiLocation, as
+// stored by the database, for the API filters.
+func apiTimestamp(t time.Time) string {
+	return t.In(apiLocation).Format("2006-01-02T15:04:05.999999999")
+}
+
+// createdAtValue converts a timestamp returned by the API into an RFC3339
+// string value, keeping sub-second precision as max_timestamp of the changes
+// data source does. Timestamps in an unknown format are kept as is.
+func createdAtValue(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	t, err := parseTimestamp(value)
+	if err != nil {
+		return types.StringValue(value)
+	}
+	return types.StringValue(t.Format(time.RFC3339Nano))
+}
+
+// createdBefore orders objects by creation timestamp, then by identifier for
+// objects created at the same time or with an unparsable timestamp.
+func createdBefore(timestampA string, idA int, timestampB string, idB int) bool {
+	a, errA := parseTimestamp(timestampA)
+	b, errB := parseTimestamp(timestampB)
+	if errA == nil && errB == nil && !a.Equal(b) {
+		return a.Before(b)
+	}
+	return idA < idB
+}
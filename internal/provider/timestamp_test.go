@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		// Paris is UTC+1 in winter and UTC+2 in summer.
+		{value: "2023-01-15T10:00:00", want: "2023-01-15T09:00:00Z"},
+		{value: "2023-07-15 10:00:00.123456", want: "2023-07-15T08:00:00.123456Z"},
+		{value: "2023-07-15T10:00:00Z", want: "2023-07-15T10:00:00Z"},
+		{value: "2023-07-15 10:00:00+05:00", want: "2023-07-15T05:00:00Z"},
+		// The last hour before the end of summer time, then the first after.
+		{value: "2023-10-29T01:30:00", want: "2023-10-28T23:30:00Z"},
+		{value: "2023-10-29T03:30:00", want: "2023-10-29T02:30:00Z"},
+		{value: "yesterday", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got, err := parseTimestamp(test.value)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("got %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.Location() != time.UTC || got.Format(time.RFC3339Nano) != test.want {
+				t.Errorf("got %s, want %s", got.Format(time.RFC3339Nano), test.want)
+			}
+		})
+	}
+}
+
+func TestCreatedAtValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "2023-05-01T10:00:00", want: "2023-05-01T08:00:00Z"},
+		{value: "2023-05-01T10:00:00.123456", want: "2023-05-01T08:00:00.123456Z"},
+		{value: "not a timestamp", want: "not a timestamp"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if got := createdAtValue(test.value).ValueString(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAPITimestamp(t *testing.T) {
+	tests := []struct {
+		value time.Time
+		want  string
+	}{
+		{value: time.Date(2023, 1, 15, 9, 0, 0, 0, time.UTC), want: "2023-01-15T10:00:00"},
+		{value: time.Date(2023, 7, 15, 8, 0, 0, 500000000, time.UTC), want: "2023-07-15T10:00:00.5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			if got := apiTimestamp(test.value); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+			parsed, err := parseTimestamp(test.want)
+			if err != nil || !parsed.Equal(test.value) {
+				t.Errorf("got %s, %v, want %s", parsed, err, test.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+// modifyPlanUniqueAddress fails the plan when the planned address of a house
+// or store is already used by another house or store of its city, addresses
+// being compared following the provider address_normalization. Only creations
+// and changes of address or city are checked. With adopt_existing, the object
+// that would be taken over, looked up by its name with the provider name
+// affixes, is not a conflict.
+func modifyPlanUniqueAddress(ctx context.Context, c *client.SendoraCityClient, n addressNormalization, names nameAffixes, kind string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || c == nil {
+		return
+	}
+
+	var cityId, id types.Int64
+	var address AddressValue
+	var name types.String
+	var adoptExisting types.Bool
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("city_id"), &cityId)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("address"), &address)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("adopt_existing"), &adoptExisting)...)
+	if kind == "store" {
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	}
+	if resp.Diagnostics.HasError() || cityId.IsNull() || cityId.IsUnknown() || address.IsNull() || address.IsUnknown() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var currentCityId types.Int64
+		var currentAddress AddressValue
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("city_id"), &currentCityId)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("address"), &currentAddress)...)
+		if resp.Diagnostics.HasError() || (cityId.Equal(currentCityId) && address.Equal(currentAddress)) {
+			return
+		}
+	}
+
+	houses, stores, err := listCityChildren(c, int(cityId.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to list city %d houses and stores, got error: %s", cityId.ValueInt64(), err))
+		return
+	}
+
+	candidate := addressCandidate{
+		kind:          kind,
+		id:            int(id.ValueInt64()),
+		address:       address.ValueString(),
+		name:          names.apiName(name.ValueString()),
+		adoptExisting: adoptExisting.ValueBool(),
+	}
+	if conflict := candidate.conflict(n, houses, stores); conflict != "" {
+		resp.Diagnostics.AddAttributeError(path.Root("address"), "Address Already In Use",
+			fmt.Sprintf("Address %q is already used by %s in city %d, and the provider enforces unique addresses.",
+				address.ValueString(), conflict, cityId.ValueInt64()))
+	}
+}
+
+// addressCandidate is a planned house or store whose address is checked
+// against the existing houses and stores of its city.
+type addressCandidate struct {
+	kind          string
+	id            int
+	address       string
+	name          string
+	adoptExisting bool
+}
+
+// conflict describes the first house or store, other than the candidate and
+// the object it would adopt, having the same address. It is empty when there
+// is none.
+func (a addressCandidate) conflict(n addressNormalization, houses []House, stores []Store) string {
+	for _, house := range houses {
+		if a.kind == "house" && (house.Id == a.id || (a.adoptExisting && house.Address == a.address)) {
+			continue
+		}
+		if n.equal(house.Address, a.address) {
+			return fmt.Sprintf("house %d at %q", house.Id, house.Address)
+		}
+	}
+	for _, store := range stores {
+		if a.kind == "store" && (store.Id == a.id || (a.adoptExisting && store.Name == a.name)) {
+			continue
+		}
+		if n.equal(store.Address, a.address) {
+			return fmt.Sprintf("store %d %q at %q", store.Id, store.Name, store.Address)
+		}
+	}
+	return ""
+}
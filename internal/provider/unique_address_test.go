@@ -0,0 +1,83 @@
+package provider
+
+import "testing"
+
+func TestAddressCandidateConflict(t *testing.T) {
+	houses := []House{
+		{Id: 1, CityId: 1, Address: "5 avenue Anatole France"},
+	}
+	stores := []Store{
+		{Id: 2, CityId: 1, Address: "1 rue de la Paix", Name: "Cocci Marche"},
+	}
+
+	tests := []struct {
+		name          string
+		normalization addressNormalization
+		candidate     addressCandidate
+		want          string
+	}{
+		{
+			name:          "free address",
+			normalization: defaultAddressNormalization,
+			candidate:     addressCandidate{kind: "house", address: "6 avenue Anatole France"},
+		},
+		{
+			name:          "new house at a house address",
+			normalization: defaultAddressNormalization,
+			candidate:     addressCandidate{kind: "house", address: "5 av. Anatole France"},
+			want:          `house 1 at "5 avenue Anatole France"`,
+		},
+		{
+			name:          "new store at a house address",
+			normalization: defaultAddressNormalization,
+			candidate:     addressCandidate{kind: "store", address: "5 avenue Anatole France", name: "Sport 2000"},
+			want:          `house 1 at "5 avenue Anatole France"`,
+		},
+		{
+			name:          "new house at a store address",
+			normalization: defaultAddressNormalization,
+			candidate:     addressCandidate{kind: "house", address: "1 Rue de la Paix"},
+			want:          `store 2 "Cocci Marche" at "1 rue de la Paix"`,
+		},
+		{
+			name:          "address compared following the provider normalization",
+			normalization: addressNormalization{},
+			candidate:     addressCandidate{kind: "house", address: "1 Rue de la Paix"},
+		},
+		{
+			name:          "existing house keeping its address",
+			normalization: defaultAddressNormalization,
+			candidate:     addressCandidate{kind: "house", id: 1, address: "5 avenue Anatole France"},
+		},
+		{
+			name:          "store with the identifier of the house",
+			normalization: defaultAddressNormalization,
+			candidate:     addressCandidate{kind: "store", id: 1, address: "5 avenue Anatole France"},
+			want:          `house 1 at "5 avenue Anatole France"`,
+		},
+		{
+			name:          "house adopting the existing house",
+			normalization: defaultAddressNormalization,
+			candidate:     addressCandidate{kind: "house", address: "5 avenue Anatole France", adoptExisting: true},
+		},
+		{
+			name:          "store adopting the existing store",
+			normalization: defaultAddressNormalization,
+			candidate:     addressCandidate{kind: "store", address: "1 rue de la Paix", name: "Cocci Marche", adoptExisting: true},
+		},
+		{
+			name:          "store adopting another store",
+			normalization: defaultAddressNormalization,
+			candidate:     addressCandidate{kind: "store", address: "1 rue de la Paix", name: "Sport 2000", adoptExisting: true},
+			want:          `store 2 "Cocci Marche" at "1 rue de la Paix"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.candidate.conflict(test.normalization, houses, stores); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
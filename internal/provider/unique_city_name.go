@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pchanvallon/terraform-provider-sendoracity/internal/client"
+)
+
+// cityNameLocks serializes the creation and renaming of cities by name within
+// the provider process, so that the uniqueness checks of the parallel
+// operations of an apply do not race.
+var cityNameLocks = &keyedMutex{}
+
+// lockCityName locks the given city name regardless of case, and returns the
+// function unlocking it.
+func lockCityName(name string) func() {
+	return cityNameLocks.lock(strings.ToLower(name))
+}
+
+// conflictingCity returns the first city named like name regardless of case,
+// other than the city with the given identifier and, with adoptExisting, the
+// city that would be adopted. It returns nil when there is none.
+func conflictingCity(cities []City, name string, id int, adoptExisting bool) *City {
+	for i, city := range cities {
+		if city.Id == id || (adoptExisting && city.Name == name) {
+			continue
+		}
+		if strings.EqualFold(city.Name, name) {
+			return &cities[i]
+		}
+	}
+	return nil
+}
+
+// checkUniqueCityName returns an error naming the city already using the given
+// name. The API name filter may be case sensitive, so every city is listed.
+func checkUniqueCityName(c *client.SendoraCityClient, attribute path.Path, name string, id int, adoptExisting bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	cities, err := listCities(c, nil)
+	if err != nil {
+		diags.AddError("Client Error",
+			fmt.Sprintf("Unable to list cities, got error: %s", err))
+		return diags
+	}
+
+	if city := conflictingCity(cities, name, id, adoptExisting); city != nil {
+		diags.AddAttributeError(attribute, "City Name Already In Use",
+			fmt.Sprintf("City name %q is already used by city %d (%q), and the provider enforces unique city names.",
+				name, city.Id, city.Name))
+	}
+	return diags
+}
+
+// modifyPlanUniqueCityName fails the plan when a city is created or renamed
+// with the name of another city. Names are compared with the provider name
+// affixes, as sent to the API.
+func modifyPlanUniqueCityName(ctx context.Context, c *client.SendoraCityClient, names nameAffixes, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || c == nil {
+		return
+	}
+
+	var name types.String
+	var adoptExisting types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("adopt_existing"), &adoptExisting)...)
+	if resp.Diagnostics.HasError() || name.IsNull() || name.IsUnknown() {
+		return
+	}
+
+	var id types.Int64
+	if !req.State.Raw.IsNull() {
+		var current types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &current)...)
+		if resp.Diagnostics.HasError() || name.Equal(current) {
+			return
+		}
+		// Existing cities are never adopted.
+		adoptExisting = types.BoolValue(false)
+	}
+
+	resp.Diagnostics.Append(checkUniqueCityName(c, path.Root("name"), names.apiName(name.ValueString()), int(id.ValueInt64()), adoptExisting.ValueBool())...)
+}
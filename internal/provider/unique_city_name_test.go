@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConflictingCity(t *testing.T) {
+	cities := []City{
+		{Id: 1, Name: "Paris"},
+		{Id: 2, Name: "Troyes"},
+	}
+
+	tests := []struct {
+		name          string
+		cityName      string
+		id            int
+		adoptExisting bool
+		want          int
+	}{
+		{name: "free name", cityName: "Reims"},
+		{name: "same name", cityName: "Paris", want: 1},
+		{name: "different case", cityName: "TROYES", want: 2},
+		{name: "city keeping its name", cityName: "paris", id: 1},
+		{name: "adopting the city", cityName: "Paris", adoptExisting: true},
+		{name: "adopting needs the exact name", cityName: "paris", adoptExisting: true, want: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := 0
+			if city := conflictingCity(cities, test.cityName, test.id, test.adoptExisting); city != nil {
+				got = city.Id
+			}
+			if got != test.want {
+				t.Errorf("got city %d, want city %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLockCityName(t *testing.T) {
+	unlock := lockCityName("Paris")
+
+	locked := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer lockCityName("PARIS")()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("the same name in another case was locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Other names are not blocked.
+	lockCityName("Troyes")()
+
+	unlock()
+	wg.Wait()
+}
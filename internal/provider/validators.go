@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maxTextLength is the size of the VARCHAR columns storing names, addresses
+// and store types.
+const maxTextLength = 255
+
+// textValidators validates names and addresses against the database
+// constraints: NOT NULL VARCHAR(255) columns, without surrounding whitespace.
+func textValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.LengthBetween(1, maxTextLength),
+		trimmedValidator{},
+	}
+}
+
+// idValidators validates identifiers generated by the database sequences.
+func idValidators() []validator.Int64 {
+	return []validator.Int64{
+		int64validator.AtLeast(1),
+	}
+}
+
+// inhabitantsValidators validates house inhabitants counts. The provider
+// maximum is checked during plan by validateMaxInhabitants.
+func inhabitantsValidators() []validator.Int64 {
+	return []validator.Int64{
+		int64validator.AtLeast(0),
+	}
+}
+
+// validateMaxInhabitants checks an inhabitants count against the provider
+// max_inhabitants setting, which is ignored when zero.
+func validateMaxInhabitants(maxInhabitants int64, attribute path.Path, value types.Int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if maxInhabitants <= 0 || value.IsNull() || value.IsUnknown() || value.ValueInt64() <= maxInhabitants {
+		return diags
+	}
+
+	diags.AddAttributeError(attribute, "Too Many Inhabitants",
+		fmt.Sprintf("Inhabitants count %d exceeds the provider max_inhabitants of %d.", value.ValueInt64(), maxInhabitants))
+	return diags
+}
+
+var _ validator.String = trimmedValidator{}
+
+// trimmedValidator rejects values with leading or trailing whitespace.
+type trimmedValidator struct{}
+
+func (v trimmedValidator) Description(ctx context.Context) string {
+	return "value must not start or end with whitespace"
+}
+
+func (v trimmedValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v trimmedValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if strings.TrimSpace(value) != value {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value))
+	}
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTextValidators(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   types.String
+		wantErr bool
+	}{
+		{name: "valid", value: types.StringValue("5 avenue Anatole France")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "empty", value: types.StringValue(""), wantErr: true},
+		{name: "leading whitespace", value: types.StringValue(" Paris"), wantErr: true},
+		{name: "trailing whitespace", value: types.StringValue("Paris\n"), wantErr: true},
+		{name: "max length", value: types.StringValue(string(make([]byte, 255)))},
+		{name: "too long", value: types.StringValue(string(make([]byte, 256))), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("name"), ConfigValue: test.value}
+			resp := &validator.StringResponse{}
+			for _, v := range textValidators() {
+				v.ValidateString(context.Background(), req, resp)
+			}
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Errorf("got errors %v, want error %t", resp.Diagnostics, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestIdValidators(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   types.Int64
+		wantErr bool
+	}{
+		{name: "valid", value: types.Int64Value(42)},
+		{name: "null", value: types.Int64Null()},
+		{name: "zero", value: types.Int64Value(0), wantErr: true},
+		{name: "negative", value: types.Int64Value(-1), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := validator.Int64Request{Path: path.Root("city_id"), ConfigValue: test.value}
+			resp := &validator.Int64Response{}
+			for _, v := range idValidators() {
+				v.ValidateInt64(context.Background(), req, resp)
+			}
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Errorf("got errors %v, want error %t", resp.Diagnostics, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMaxInhabitants(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int64
+		value   types.Int64
+		wantErr bool
+	}{
+		{name: "no maximum", max: 0, value: types.Int64Value(1000)},
+		{name: "below maximum", max: 10, value: types.Int64Value(9)},
+		{name: "at maximum", max: 10, value: types.Int64Value(10)},
+		{name: "above maximum", max: 10, value: types.Int64Value(11), wantErr: true},
+		{name: "null", max: 10, value: types.Int64Null()},
+		{name: "unknown", max: 10, value: types.Int64Unknown()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diags := validateMaxInhabitants(test.max, path.Root("inhabitants"), test.value)
+			if diags.HasError() != test.wantErr {
+				t.Errorf("got errors %v, want error %t", diags, test.wantErr)
+			}
+		})
+	}
+}